@@ -8,8 +8,10 @@ import (
 	"rsshub/internal/adapters/cli"
 	"rsshub/internal/adapters/http"
 	"rsshub/internal/adapters/postgres"
+	"rsshub/internal/adapters/secrets"
 	"rsshub/internal/config"
 	"rsshub/internal/core/services"
+	"rsshub/internal/domain"
 )
 
 func main() {
@@ -30,22 +32,63 @@ func run() error {
 
 	feedRepo := postgres.NewFeedRepository(db)
 	articleRepo := postgres.NewArticleRepository(db)
-	rssFetcher := http.NewRSSFetcher()
+	fetchRunRepo := postgres.NewFetchRunRepository(db)
+	hostStateRepo := postgres.NewHostStateRepository(db)
+	secretKey, err := cfg.GetSecretKey()
+	if err != nil {
+		return err
+	}
+	previousSecretKey, err := cfg.GetPreviousSecretKey()
+	if err != nil {
+		return err
+	}
+	secretCipher, err := secrets.NewAESGCMCipher(secretKey, previousSecretKey)
+	if err != nil {
+		return fmt.Errorf("failed to load secret key: %w", err)
+	}
+
+	transport := http.TransportConfig{
+		ProxyURL:       cfg.GetProxyURL(),
+		CABundlePath:   cfg.GetCABundlePath(),
+		ClientCertPath: cfg.GetClientCertPath(),
+		ClientKeyPath:  cfg.GetClientKeyPath(),
+		MinTLSVersion:  cfg.GetMinTLSVersion(),
+	}
+	rssFetcher, err := http.NewRSSFetcher(transport, secretCipher, cfg.GetMaxFeedBytes(), cfg.GetMaxFeedItems())
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
 	ipcLock := postgres.NewIPCLock(db)
 
-	feedService := services.NewFeedService(feedRepo)
+	canonicalizer := domain.NewURLCanonicalizer(cfg.GetStrippedParams())
+
+	feedService := services.NewFeedService(feedRepo, rssFetcher, rssFetcher, secretCipher, canonicalizer)
 	articleService := services.NewArticleService(articleRepo)
+	historyService := services.NewFetchHistoryService(fetchRunRepo)
+	hostStatusService := services.NewHostStatusService(hostStateRepo)
+	hostLimiter := services.NewHostLimiter(rssFetcher, cfg.GetHostMaxConcurrent(), cfg.GetHostMinDelay())
+	hostBreaker := services.NewHostBreaker(hostLimiter, cfg.GetBreakerThreshold(), cfg.GetBreakerCooldown())
 
 	aggregatorService := services.NewAggregatorService(
 		feedRepo,
 		articleRepo,
-		rssFetcher,
+		fetchRunRepo,
+		hostStateRepo,
+		hostBreaker,
 		ipcLock,
+		canonicalizer,
+		domain.FeedPolicy{
+			RedirectThreshold: cfg.GetRedirectThreshold(),
+			GoneThreshold:     cfg.GetGoneThreshold(),
+			BackoffBase:       cfg.GetBackoffBase(),
+			BackoffMax:        cfg.GetBackoffMax(),
+		},
+		cfg.GetFetchHistoryRetention(),
 		cfg.GetDefaultInterval(),
 		cfg.GetDefaultWorkersCount(),
 	)
 
-	handler := cli.NewHandler(feedService, articleService, aggregatorService, db)
+	handler := cli.NewHandler(feedService, articleService, historyService, hostStatusService, aggregatorService, db)
 
 	if len(os.Args) > 1 && os.Args[1] == "fetch" {
 		log.Println("Running migrations...")
@@ -2,40 +2,232 @@ package cli
 
 import (
 	"fmt"
+	"net/textproto"
 	"strconv"
+	"strings"
 	"time"
+
+	"rsshub/internal/core/services"
+	"rsshub/internal/domain"
 )
 
-func parseAddFlags(args []string) (name, url string, err error) {
+func parseAddFlags(args []string) (name, url string, opts services.AddFeedOptions, err error) {
 	if len(args) < 4 {
-		return "", "", fmt.Errorf("usage: rsshub add --name <name> --url <url>")
+		return "", "", opts, fmt.Errorf("usage: rsshub add --name <name> --url <url> [--strip-params <p1,p2>] [--skip-validate] " +
+			"[--basic-auth <user:password> | --bearer-token <token> | --cookie <cookie>] [--header <\"Name: value\">]... " +
+			"[--proxy <url|direct>]")
 	}
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "--name":
 			if i+1 >= len(args) {
-				return "", "", fmt.Errorf("--name requires a value")
+				return "", "", opts, fmt.Errorf("--name requires a value")
 			}
 			name = args[i+1]
 			i++
 		case "--url":
 			if i+1 >= len(args) {
-				return "", "", fmt.Errorf("--url requires a value")
+				return "", "", opts, fmt.Errorf("--url requires a value")
 			}
 			url = args[i+1]
 			i++
+		case "--strip-params":
+			if i+1 >= len(args) {
+				return "", "", opts, fmt.Errorf("--strip-params requires a value")
+			}
+			opts.StripParams = splitList(args[i+1])
+			i++
+		case "--skip-validate":
+			opts.SkipValidate = true
+		case "--basic-auth", "--bearer-token", "--cookie":
+			if i+1 >= len(args) {
+				return "", "", opts, fmt.Errorf("%s requires a value", args[i])
+			}
+			if opts.Auth, err = parseAuthFlag(args[i], args[i+1]); err != nil {
+				return "", "", opts, err
+			}
+			i++
+		case "--header":
+			if i+1 >= len(args) {
+				return "", "", opts, fmt.Errorf("--header requires a value")
+			}
+			header, value, err := parseHeaderFlag(args[i+1])
+			if err != nil {
+				return "", "", opts, err
+			}
+			if opts.Headers == nil {
+				opts.Headers = make(map[string]string)
+			}
+			opts.Headers[header] = value
+			i++
+		case "--proxy":
+			if i+1 >= len(args) {
+				return "", "", opts, fmt.Errorf("--proxy requires a value")
+			}
+			if opts.ProxyURL, err = parseProxyFlag(args[i+1]); err != nil {
+				return "", "", opts, err
+			}
+			i++
 		}
 	}
 
 	if name == "" {
-		return "", "", fmt.Errorf("--name is required")
+		return "", "", opts, fmt.Errorf("--name is required")
 	}
 	if url == "" {
-		return "", "", fmt.Errorf("--url is required")
+		return "", "", opts, fmt.Errorf("--url is required")
+	}
+
+	return name, url, opts, nil
+}
+
+// parseEditFlags reads --name and the request option changes: new
+// credentials or --no-auth, headers to set or remove, and a proxy or
+// --reset-proxy to go back to the configured one.
+func parseEditFlags(args []string) (name string, opts services.EditFeedOptions, err error) {
+	if len(args) < 3 {
+		return "", opts, fmt.Errorf("usage: rsshub edit --name <name> " +
+			"[--basic-auth <user:password> | --bearer-token <token> | --cookie <cookie> | --no-auth] " +
+			"[--header <\"Name: value\">]... [--remove-header <name>]... [--proxy <url|direct> | --reset-proxy]")
+	}
+
+	changed := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--name":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("--name requires a value")
+			}
+			name = args[i+1]
+			i++
+		case "--basic-auth", "--bearer-token", "--cookie":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("%s requires a value", args[i])
+			}
+			auth, err := parseAuthFlag(args[i], args[i+1])
+			if err != nil {
+				return "", opts, err
+			}
+			opts.Auth = &auth
+			changed = true
+			i++
+		case "--no-auth":
+			opts.Auth = &domain.FeedAuth{}
+			changed = true
+		case "--header":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("--header requires a value")
+			}
+			header, value, err := parseHeaderFlag(args[i+1])
+			if err != nil {
+				return "", opts, err
+			}
+			if opts.SetHeaders == nil {
+				opts.SetHeaders = make(map[string]string)
+			}
+			opts.SetHeaders[header] = value
+			changed = true
+			i++
+		case "--remove-header":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("--remove-header requires a value")
+			}
+			opts.RemoveHeaders = append(opts.RemoveHeaders, textproto.CanonicalMIMEHeaderKey(args[i+1]))
+			changed = true
+			i++
+		case "--proxy":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("--proxy requires a value")
+			}
+			proxyURL, err := parseProxyFlag(args[i+1])
+			if err != nil {
+				return "", opts, err
+			}
+			opts.ProxyURL = &proxyURL
+			changed = true
+			i++
+		case "--reset-proxy":
+			proxyURL := ""
+			opts.ProxyURL = &proxyURL
+			changed = true
+		}
+	}
+
+	if name == "" {
+		return "", opts, fmt.Errorf("--name is required")
+	}
+	if !changed {
+		return "", opts, fmt.Errorf("nothing to change")
+	}
+
+	return name, opts, nil
+}
+
+func parseAuthFlag(flag, value string) (domain.FeedAuth, error) {
+	if value == "" {
+		return domain.FeedAuth{}, fmt.Errorf("%s cannot be empty", flag)
+	}
+
+	switch flag {
+	case "--basic-auth":
+		username, password, ok := strings.Cut(value, ":")
+		if !ok || username == "" {
+			return domain.FeedAuth{}, fmt.Errorf("--basic-auth must be in the form user:password")
+		}
+		return domain.FeedAuth{Type: domain.AuthBasic, Username: username, Secret: password}, nil
+	case "--bearer-token":
+		return domain.FeedAuth{Type: domain.AuthBearer, Secret: value}, nil
+	default:
+		return domain.FeedAuth{Type: domain.AuthCookie, Secret: value}, nil
+	}
+}
+
+func parseProxyFlag(value string) (string, error) {
+	if value == domain.ProxyDirect {
+		return value, nil
+	}
+	if err := domain.ValidateProxyURL(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// parseHeaderFlag splits a "Name: value" header and canonicalizes the name.
+func parseHeaderFlag(value string) (string, string, error) {
+	name, headerValue, ok := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid header %q, expected \"Name: value\"", value)
+	}
+	return textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(headerValue), nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseDiscoverFlags(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("usage: rsshub discover --url <url>")
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--url" {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("--url requires a value")
+			}
+			return args[i+1], nil
+		}
 	}
 
-	return name, url, nil
+	return "", fmt.Errorf("--url is required")
 }
 
 func parseSetIntervalFlags(args []string) (time.Duration, error) {
@@ -119,6 +311,96 @@ func parseListFlags(args []string) (int, error) {
 	return 0, nil
 }
 
+// parseSetScheduleFlags reads --name and --interval. An interval of "auto"
+// clears the override and is returned as zero.
+func parseSetScheduleFlags(args []string) (name string, interval time.Duration, err error) {
+	if len(args) < 4 {
+		return "", 0, fmt.Errorf("usage: rsshub set-schedule --name <name> --interval <duration|auto>")
+	}
+
+	var intervalStr string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--name":
+			if i+1 >= len(args) {
+				return "", 0, fmt.Errorf("--name requires a value")
+			}
+			name = args[i+1]
+			i++
+		case "--interval":
+			if i+1 >= len(args) {
+				return "", 0, fmt.Errorf("--interval requires a value")
+			}
+			intervalStr = args[i+1]
+			i++
+		}
+	}
+
+	if name == "" {
+		return "", 0, fmt.Errorf("--name is required")
+	}
+	if intervalStr == "" {
+		return "", 0, fmt.Errorf("--interval is required")
+	}
+	if intervalStr == "auto" {
+		return name, 0, nil
+	}
+
+	interval, err = time.ParseDuration(intervalStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid interval format: %w", err)
+	}
+	if interval <= 0 {
+		return "", 0, fmt.Errorf("interval must be greater than 0")
+	}
+
+	return name, interval, nil
+}
+
+func parseFeedListFlags(args []string) (num int, status domain.FeedStatus, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--num":
+			if i+1 >= len(args) {
+				return 0, "", fmt.Errorf("--num requires a value")
+			}
+			num, err = strconv.Atoi(args[i+1])
+			if err != nil {
+				return 0, "", fmt.Errorf("invalid num format: %w", err)
+			}
+			i++
+		case "--status":
+			if i+1 >= len(args) {
+				return 0, "", fmt.Errorf("--status requires a value")
+			}
+			status, err = domain.ParseFeedStatus(args[i+1])
+			if err != nil {
+				return 0, "", err
+			}
+			i++
+		}
+	}
+
+	return num, status, nil
+}
+
+func parseStatusFlags(command string, args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("usage: rsshub %s --name <name>", command)
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--name" {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("--name requires a value")
+			}
+			return args[i+1], nil
+		}
+	}
+
+	return "", fmt.Errorf("--name is required")
+}
+
 func parseDeleteFlags(args []string) (string, error) {
 	if len(args) < 2 {
 		return "", fmt.Errorf("usage: rsshub delete --name <name>")
@@ -136,9 +418,9 @@ func parseDeleteFlags(args []string) (string, error) {
 	return "", fmt.Errorf("--name is required")
 }
 
-func parseArticlesFlags(args []string) (feedName string, num int, err error) {
+func parseArticlesFlags(args []string) (feedName, category string, num int, err error) {
 	if len(args) < 2 {
-		return "", 0, fmt.Errorf("usage: rsshub articles --feed-name <name> [--num <count>]")
+		return "", "", 0, fmt.Errorf("usage: rsshub articles --feed-name <name> [--category <category>] [--num <count>]")
 	}
 
 	num = 3 // default
@@ -147,26 +429,58 @@ func parseArticlesFlags(args []string) (feedName string, num int, err error) {
 		switch args[i] {
 		case "--feed-name":
 			if i+1 >= len(args) {
-				return "", 0, fmt.Errorf("--feed-name requires a value")
+				return "", "", 0, fmt.Errorf("--feed-name requires a value")
 			}
 			feedName = args[i+1]
 			i++
+		case "--category":
+			if i+1 >= len(args) {
+				return "", "", 0, fmt.Errorf("--category requires a value")
+			}
+			category = args[i+1]
+			i++
 		case "--num":
 			if i+1 >= len(args) {
-				return "", 0, fmt.Errorf("--num requires a value")
+				return "", "", 0, fmt.Errorf("--num requires a value")
 			}
 			parsedNum, err := strconv.Atoi(args[i+1])
 			if err != nil {
-				return "", 0, fmt.Errorf("invalid num format: %w", err)
+				return "", "", 0, fmt.Errorf("invalid num format: %w", err)
 			}
 			num = parsedNum
 			i++
 		}
 	}
 
-	if feedName == "" {
-		return "", 0, fmt.Errorf("--feed-name is required")
+	if feedName == "" && category == "" {
+		return "", "", 0, fmt.Errorf("--feed-name or --category is required")
+	}
+
+	return feedName, category, num, nil
+}
+
+func parseHistoryFlags(args []string) (filter domain.FetchRunFilter, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--feed-name":
+			if i+1 >= len(args) {
+				return filter, fmt.Errorf("--feed-name requires a value")
+			}
+			filter.FeedName = args[i+1]
+			i++
+		case "--failed":
+			filter.FailedOnly = true
+		case "--num":
+			if i+1 >= len(args) {
+				return filter, fmt.Errorf("--num requires a value")
+			}
+			filter.Limit, err = strconv.Atoi(args[i+1])
+			if err != nil {
+				return filter, fmt.Errorf("invalid num format: %w", err)
+			}
+			i++
+		}
 	}
 
-	return feedName, num, nil
+	return filter, nil
 }
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"rsshub/internal/domain"
+)
+
+func formatEnclosure(enclosure domain.Enclosure) string {
+	var details []string
+	if enclosure.MimeType != "" {
+		details = append(details, enclosure.MimeType)
+	}
+	if enclosure.Length > 0 {
+		details = append(details, formatBytes(enclosure.Length))
+	}
+	if enclosure.Duration > 0 {
+		details = append(details, enclosure.Duration.String())
+	}
+
+	if len(details) == 0 {
+		return enclosure.URL
+	}
+	return fmt.Sprintf("%s (%s)", enclosure.URL, strings.Join(details, ", "))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatURL hides the password of a URL that carries credentials.
+func formatURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.Redacted()
+}
+
+// formatHeaders lists custom request headers by name, masking the values
+// of those that carry credentials.
+func formatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, domain.MaskHeader(name, headers[name])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printRequestOptions(feed *domain.Feed) {
+	if !feed.Auth.IsZero() {
+		fmt.Printf("   Auth: %s\n", feed.Auth)
+	}
+	if len(feed.Headers) > 0 {
+		fmt.Printf("   Headers: %s\n", formatHeaders(feed.Headers))
+	}
+	if feed.ProxyURL != "" {
+		fmt.Printf("   Proxy: %s\n", formatProxy(feed.ProxyURL))
+	}
+}
+
+// formatProxy shows whether a feed bypasses the proxy; any other proxy URL
+// is stored encrypted and shown masked.
+func formatProxy(proxyURL string) string {
+	if proxyURL == domain.ProxyDirect {
+		return proxyURL
+	}
+	return domain.MaskSecret(proxyURL)
+}
+
+func formatNextFetch(nextFetchAt *time.Time) string {
+	if nextFetchAt == nil || !nextFetchAt.After(time.Now()) {
+		return "on the next tick"
+	}
+	return "after " + nextFetchAt.Format("2006-01-02 15:04")
+}
+
+func formatScheduleHints(hints domain.ScheduleHints) string {
+	var parts []string
+	if hints.TTL > 0 {
+		parts = append(parts, fmt.Sprintf("ttl %v", hints.TTL))
+	}
+	if hints.UpdatePeriod > 0 {
+		parts = append(parts, fmt.Sprintf("updates every %v", hints.UpdatePeriod))
+	}
+	if len(hints.SkipHours) > 0 {
+		hours := make([]string, 0, len(hints.SkipHours))
+		for _, hour := range hints.SkipHours {
+			hours = append(hours, fmt.Sprintf("%02d", hour))
+		}
+		parts = append(parts, "skips hours "+strings.Join(hours, ",")+" UTC")
+	}
+	if len(hints.SkipDays) > 0 {
+		days := make([]string, 0, len(hints.SkipDays))
+		for _, day := range hints.SkipDays {
+			days = append(days, day.String())
+		}
+		parts = append(parts, "skips "+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"rsshub/internal/core/services"
 	"rsshub/internal/domain"
@@ -16,6 +18,8 @@ import (
 type Handler struct {
 	feedService    *services.FeedService
 	articleService *services.ArticleService
+	historyService *services.FetchHistoryService
+	hostService    *services.HostStatusService
 	aggregator     ports.AggregatorPort
 	migrator       ports.Migrator
 }
@@ -23,12 +27,16 @@ type Handler struct {
 func NewHandler(
 	feedService *services.FeedService,
 	articleService *services.ArticleService,
+	historyService *services.FetchHistoryService,
+	hostService *services.HostStatusService,
 	aggregator ports.AggregatorPort,
 	migrator ports.Migrator,
 ) *Handler {
 	return &Handler{
 		feedService:    feedService,
 		articleService: articleService,
+		historyService: historyService,
+		hostService:    hostService,
 		aggregator:     aggregator,
 		migrator:       migrator,
 	}
@@ -50,16 +58,34 @@ func (h *Handler) Run(args []string) error {
 		return h.HandleFetch()
 	case "add":
 		return h.HandleAdd(args[2:])
+	case "edit":
+		return h.HandleEdit(args[2:])
+	case "rotate-key":
+		return h.HandleRotateKey()
+	case "discover":
+		return h.HandleDiscover(args[2:])
 	case "set-interval":
 		return h.HandleSetInterval(args[2:])
 	case "set-workers":
 		return h.HandleSetWorkers(args[2:])
+	case "set-schedule":
+		return h.HandleSetSchedule(args[2:])
 	case "list":
 		return h.HandleList(args[2:])
 	case "delete":
 		return h.HandleDelete(args[2:])
+	case "pause":
+		return h.HandleSetStatus("pause", domain.FeedStatusPaused, args[2:])
+	case "activate":
+		return h.HandleSetStatus("activate", domain.FeedStatusActive, args[2:])
 	case "articles":
 		return h.HandleArticles(args[2:])
+	case "stories":
+		return h.HandleStories(args[2:])
+	case "history":
+		return h.HandleHistory(args[2:])
+	case "status":
+		return h.HandleStatus()
 	case "--help", "-h", "help":
 		return h.ShowHelp()
 	default:
@@ -93,20 +119,83 @@ func (h *Handler) HandleFetch() error {
 }
 
 func (h *Handler) HandleAdd(args []string) error {
-	name, url, err := parseAddFlags(args)
+	name, url, opts, err := parseAddFlags(args)
 	if err != nil {
 		return err
 	}
 
 	ctx := context.Background()
-	if err := h.feedService.AddFeed(ctx, name, url); err != nil {
+	feed, preview, err := h.feedService.AddFeed(ctx, name, url, opts)
+	if err != nil {
+		var multipleErr *domain.MultipleFeedsError
+		if errors.As(err, &multipleErr) {
+			fmt.Printf("Several feeds were found at %s, re-run add with one of them:\n", url)
+			printCandidates(multipleErr.Candidates)
+		}
 		return fmt.Errorf("failed to add feed: %w", err)
 	}
 
+	if feed.URL != url {
+		fmt.Printf("Feed URL: %s\n", formatURL(feed.URL))
+	}
+	if preview != nil {
+		fmt.Printf("Channel: %s (%d items)\n", preview.Title, len(preview.Items))
+	}
 	fmt.Printf("Feed '%s' added successfully\n", name)
 	return nil
 }
 
+func (h *Handler) HandleEdit(args []string) error {
+	name, opts, err := parseEditFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	feed, err := h.feedService.EditFeed(ctx, name, opts)
+	if err != nil {
+		return fmt.Errorf("failed to edit feed: %w", err)
+	}
+
+	fmt.Printf("Feed '%s' updated\n", name)
+	printRequestOptions(feed)
+	return nil
+}
+
+func (h *Handler) HandleRotateKey() error {
+	ctx := context.Background()
+	rotated, err := h.feedService.RotateSecrets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to rotate key after %d feeds: %w", rotated, err)
+	}
+
+	fmt.Printf("Re-encrypted secrets of %d feeds with the current key\n", rotated)
+	return nil
+}
+
+func (h *Handler) HandleDiscover(args []string) error {
+	url, err := parseDiscoverFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	candidates, err := h.feedService.DiscoverFeeds(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feeds found at %s:\n", url)
+	printCandidates(candidates)
+	return nil
+}
+
+func printCandidates(candidates []string) {
+	for i, candidate := range candidates {
+		fmt.Printf("  %d. %s\n", i+1, candidate)
+	}
+}
+
 func (h *Handler) HandleSetInterval(args []string) error {
 	duration, err := parseSetIntervalFlags(args)
 	if err != nil {
@@ -137,20 +226,48 @@ func (h *Handler) HandleSetWorkers(args []string) error {
 	return nil
 }
 
+func (h *Handler) HandleSetSchedule(args []string) error {
+	name, interval, err := parseSetScheduleFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := h.feedService.SetFeedInterval(ctx, name, interval); err != nil {
+		return fmt.Errorf("failed to set schedule: %w", err)
+	}
+
+	if interval == 0 {
+		fmt.Printf("Feed '%s' now follows the publisher's schedule hints\n", name)
+	} else {
+		fmt.Printf("Feed '%s' will be fetched every %v\n", name, interval)
+	}
+	return nil
+}
+
 func (h *Handler) HandleList(args []string) error {
-	num, err := parseListFlags(args)
+	num, status, err := parseFeedListFlags(args)
 	if err != nil {
 		return err
 	}
 
 	ctx := context.Background()
-	feeds, err := h.feedService.ListFeeds(ctx, num)
+	var feeds []*domain.Feed
+	if status != "" {
+		feeds, err = h.feedService.ListFeedsByStatus(ctx, status)
+	} else {
+		feeds, err = h.feedService.ListFeeds(ctx, num)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to list feeds: %w", err)
 	}
 
 	if len(feeds) == 0 {
-		fmt.Println("No feeds available")
+		if status != "" {
+			fmt.Printf("No %s feeds\n", status)
+		} else {
+			fmt.Println("No feeds available")
+		}
 		return nil
 	}
 
@@ -158,7 +275,31 @@ func (h *Handler) HandleList(args []string) error {
 	fmt.Println()
 	for i, feed := range feeds {
 		fmt.Printf("%d. Name: %s\n", i+1, feed.Name)
-		fmt.Printf("   URL: %s\n", feed.URL)
+		fmt.Printf("   URL: %s\n", formatURL(feed.URL))
+		if feed.Status != domain.FeedStatusActive {
+			fmt.Printf("   Status: %s\n", feed.Status)
+		}
+		if len(feed.StripParams) > 0 {
+			fmt.Printf("   Stripped params: %s\n", strings.Join(feed.StripParams, ", "))
+		}
+		if feed.IntervalOverride > 0 {
+			fmt.Printf("   Schedule: every %v (override)\n", feed.IntervalOverride)
+		} else if !feed.Schedule.IsZero() {
+			fmt.Printf("   Schedule: %s (publisher hints)\n", formatScheduleHints(feed.Schedule))
+		}
+		if feed.ConsecutiveFailures > 0 {
+			fmt.Printf("   Failing: %d times in a row, next attempt %s\n",
+				feed.ConsecutiveFailures, formatNextFetch(feed.NextFetchAt))
+		} else if feed.NextFetchAt != nil && feed.NextFetchAt.After(time.Now()) {
+			fmt.Printf("   Next fetch: %s\n", formatNextFetch(feed.NextFetchAt))
+		}
+		if feed.LastError != "" && feed.LastErrorAt != nil {
+			fmt.Printf("   Last error: %s (%s)\n", feed.LastError, feed.LastErrorAt.Format("2006-01-02 15:04"))
+		}
+		if feed.RedirectCount > 0 {
+			fmt.Printf("   Redirects to: %s (%d times in a row)\n", formatURL(feed.RedirectURL), feed.RedirectCount)
+		}
+		printRequestOptions(feed)
 		fmt.Printf("   Added: %s\n", feed.CreatedAt.Format("2006-01-02 15:04"))
 		fmt.Println()
 	}
@@ -181,60 +322,251 @@ func (h *Handler) HandleDelete(args []string) error {
 	return nil
 }
 
+func (h *Handler) HandleSetStatus(command string, status domain.FeedStatus, args []string) error {
+	name, err := parseStatusFlags(command, args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := h.feedService.SetFeedStatus(ctx, name, status); err != nil {
+		return fmt.Errorf("failed to %s feed: %w", command, err)
+	}
+
+	fmt.Printf("Feed '%s' is now %s\n", name, status)
+	return nil
+}
+
 func (h *Handler) HandleArticles(args []string) error {
-	feedName, num, err := parseArticlesFlags(args)
+	feedName, category, num, err := parseArticlesFlags(args)
 	if err != nil {
 		return err
 	}
 
 	ctx := context.Background()
-	articles, err := h.articleService.GetArticlesByFeed(ctx, feedName, num)
+	var articles []*domain.Article
+	if category != "" {
+		articles, err = h.articleService.GetArticlesByCategory(ctx, feedName, category, num)
+	} else {
+		articles, err = h.articleService.GetArticlesByFeed(ctx, feedName, num)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get articles: %w", err)
 	}
 
 	if len(articles) == 0 {
-		fmt.Printf("No articles found for feed '%s'\n", feedName)
+		if category != "" {
+			fmt.Printf("No articles found in category '%s'\n", category)
+		} else {
+			fmt.Printf("No articles found for feed '%s'\n", feedName)
+		}
 		return nil
 	}
 
-	fmt.Printf("Feed: %s\n\n", feedName)
+	if feedName != "" {
+		fmt.Printf("Feed: %s\n", feedName)
+	}
+	if category != "" {
+		fmt.Printf("Category: %s\n", category)
+	}
+	fmt.Println()
 	for i, article := range articles {
 		var date string
 		if article.PublishedAt != nil {
 			date = article.PublishedAt.Format("2006-01-02")
+			if article.PublishedAtInferred {
+				date = "~" + date
+			}
 		} else {
 			date = article.CreatedAt.Format("2006-01-02")
 		}
 
 		fmt.Printf("%d. [%s] %s\n", i+1, date, article.Title)
+		if article.Author != "" {
+			fmt.Printf("   By: %s\n", article.Author)
+		}
+		if len(article.Categories) > 0 {
+			fmt.Printf("   Categories: %s\n", strings.Join(article.Categories, ", "))
+		}
+		for _, enclosure := range article.Enclosures {
+			fmt.Printf("   Media: %s\n", formatEnclosure(enclosure))
+			if enclosure.ThumbnailURL != "" {
+				fmt.Printf("   Thumbnail: %s\n", enclosure.ThumbnailURL)
+			}
+		}
 		fmt.Printf("   %s\n\n", article.Link)
 	}
 
 	return nil
 }
 
+func (h *Handler) HandleStories(args []string) error {
+	num, err := parseListFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	stories, err := h.articleService.GetStories(ctx, num)
+	if err != nil {
+		return fmt.Errorf("failed to get stories: %w", err)
+	}
+
+	if len(stories) == 0 {
+		fmt.Println("No stories found")
+		return nil
+	}
+
+	fmt.Println("# Latest Stories")
+	fmt.Println()
+	for i, story := range stories {
+		headline := story.Headline()
+		date := headline.CreatedAt
+		if headline.PublishedAt != nil {
+			date = *headline.PublishedAt
+		}
+
+		fmt.Printf("%d. [%s] %s\n", i+1, date.Format("2006-01-02"), headline.Title)
+		if len(story.Sources) == 1 {
+			fmt.Printf("   %s: %s\n\n", headline.FeedName, headline.Link)
+			continue
+		}
+
+		fmt.Printf("   %d sources:\n", len(story.Sources))
+		for _, source := range story.Sources {
+			fmt.Printf("   - %s: %s\n", source.FeedName, source.Link)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func (h *Handler) HandleHistory(args []string) error {
+	filter, err := parseHistoryFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	runs, err := h.historyService.GetHistory(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to get fetch history: %w", err)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No fetch runs found")
+		return nil
+	}
+
+	fmt.Println("# Fetch History")
+	fmt.Println()
+	for i, run := range runs {
+		outcome := "ok"
+		if run.Failed() {
+			outcome = "failed"
+		}
+		fmt.Printf("%d. [%s] %s: %s in %v\n", i+1, run.StartedAt.Format("2006-01-02 15:04:05"),
+			run.FeedName, outcome, run.Duration.Round(time.Millisecond))
+		if run.StatusCode != 0 {
+			fmt.Printf("   HTTP %d, %s, %d items, %d new\n",
+				run.StatusCode, formatBytes(run.BytesRead), run.ItemsSeen, run.NewArticles)
+		}
+		if run.Truncated {
+			fmt.Println("   Truncated: size or item limit reached, later items were skipped")
+		}
+		if run.Failed() {
+			fmt.Printf("   Error: %s\n", run.Error)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func (h *Handler) HandleStatus() error {
+	ctx := context.Background()
+	states, err := h.hostService.ListHostStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get host status: %w", err)
+	}
+
+	if len(states) == 0 {
+		fmt.Println("No host status published yet (is the aggregator running?)")
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Println("# Hosts")
+	fmt.Println()
+	for _, state := range states {
+		throttle := "ok"
+		if state.IsPaused(now) {
+			throttle = fmt.Sprintf("paused until %s (%s)", state.PausedUntil.Format("15:04:05"), state.PauseReason)
+		}
+		breaker := string(state.Breaker)
+		if state.IsBreakerOpen(now) {
+			breaker = fmt.Sprintf("open until %s", state.BreakerOpenUntil.Format("15:04:05"))
+		}
+		if state.BreakerFailures > 0 {
+			breaker = fmt.Sprintf("%s, %d failures", breaker, state.BreakerFailures)
+		}
+		fmt.Printf("%s: %s, %d in flight, %d requests, circuit %s\n",
+			state.Host, throttle, state.InFlight, state.Requests, breaker)
+	}
+	fmt.Printf("\nLast updated: %s\n", states[0].UpdatedAt.Format("2006-01-02 15:04:05"))
+
+	return nil
+}
+
 func (h *Handler) ShowHelp() error {
 	help := `
 Usage:
   rsshub COMMAND [OPTIONS]
 
 Common Commands:
-  add             add new RSS feed
+  add             add new RSS feed (website URLs are resolved to their feed)
+  edit            change a feed's credentials, custom request headers and proxy
+  rotate-key      re-encrypt stored feed secrets with CLI_APP_SECRET_KEY (old key in CLI_APP_PREVIOUS_SECRET_KEY)
+  discover        list feeds advertised by a website
   set-interval    set RSS fetch interval
   set-workers     set number of workers
-  list            list available RSS feeds
+  set-schedule    fetch a feed at a fixed interval instead of following its publisher's hints
+  list            list available RSS feeds (--status active|paused|gone|erroring filters them)
   delete          delete RSS feed
-  articles        show latest articles
+  pause           stop fetching a feed
+  activate        resume fetching a paused, gone or erroring feed
+  articles        show latest articles (dates marked ~ were inferred, not published by the feed)
+  stories         show latest stories, collapsing near-duplicate articles across feeds
+  history         show recent fetch runs (--feed-name, --failed, --num)
+  status          show per-host throttle and circuit breaker state of the running aggregator
   fetch           starts the background process that periodically fetches and processes RSS feeds using a worker pool
 
 Examples:
   rsshub add --name "tech-crunch" --url "https://techcrunch.com/feed/"
+  rsshub add --name "intranet" --url "https://intranet.local/feed" --skip-validate
+  rsshub add --name "hn" --url "https://news.ycombinator.com/rss" --strip-params "sid,src"
+  rsshub add --name "partner" --url "https://partner.example/feed" --bearer-token "$TOKEN" --header "Accept: application/rss+xml"
+  rsshub edit --name "partner" --basic-auth "alice:s3cret" --remove-header "Accept"
+  rsshub edit --name "partner" --no-auth
+  rsshub edit --name "intranet" --proxy direct
+  rsshub edit --name "partner" --proxy "socks5://127.0.0.1:1080"
+  rsshub rotate-key
+  rsshub discover --url "https://techcrunch.com"
   rsshub set-interval --duration 2m
   rsshub set-workers --count 5
+  rsshub set-schedule --name "tech-crunch" --interval 1h
+  rsshub set-schedule --name "tech-crunch" --interval auto
   rsshub list --num 5
+  rsshub list --status gone
+  rsshub pause --name "tech-crunch"
+  rsshub activate --name "tech-crunch"
   rsshub delete --name "tech-crunch"
   rsshub articles --feed-name "tech-crunch" --num 5
+  rsshub articles --category "AI" --num 10
+  rsshub stories --num 10
+  rsshub history --feed-name "tech-crunch" --failed --num 20
+  rsshub status
   rsshub fetch
 `
 	fmt.Println(help)
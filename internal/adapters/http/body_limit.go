@@ -0,0 +1,54 @@
+package http
+
+import (
+	"fmt"
+	"io"
+
+	"rsshub/internal/domain"
+)
+
+// DefaultMaxBodyBytes caps a response body when no limit is configured.
+const DefaultMaxBodyBytes = 10 << 20
+
+// limitedBody reads at most limit bytes and then reports EOF, remembering
+// that the body was cut so the caller can flag the result as truncated.
+type limitedBody struct {
+	r        io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func newLimitedBody(r io.Reader, limit int64) *limitedBody {
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	return &limitedBody{r: r, limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.read >= b.limit {
+		// Probe for one more byte to tell a body of exactly limit bytes
+		// from a longer one.
+		var probe [1]byte
+		if n, _ := b.r.Read(probe[:]); n > 0 {
+			b.exceeded = true
+		}
+		return 0, io.EOF
+	}
+
+	if remaining := b.limit - b.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *limitedBody) report(parsed *domain.ParsedFeed) {
+	if b.exceeded {
+		parsed.Truncated = true
+		parsed.Warnings = append(parsed.Warnings,
+			fmt.Sprintf("response body exceeded %d bytes, feed was cut short", b.limit))
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+var xmlEncodingDecl = regexp.MustCompile(`^(\s*<\?xml[^>]*?encoding\s*=\s*["'])([A-Za-z0-9._:-]+)(["'])`)
+
+// toUTF8 transcodes a feed body to UTF-8. The XML prolog is trusted over
+// the Content-Type header because publishers fix their templates more
+// often than their server configuration. After transcoding, the prolog is
+// rewritten so the XML decoder does not try to convert the body again.
+func toUTF8(body []byte, contentType string) ([]byte, error) {
+	body = trimUTF8BOM(body)
+
+	label := ""
+	if match := xmlEncodingDecl.FindSubmatch(body); match != nil {
+		label = string(match[2])
+	}
+	if label == "" {
+		if _, params, err := mime.ParseMediaType(contentType); err == nil {
+			label = params["charset"]
+		}
+	}
+
+	if label == "" || isUTF8Label(label) {
+		return body, nil
+	}
+
+	encoding, name := charset.Lookup(label)
+	if encoding == nil {
+		return nil, fmt.Errorf("unsupported charset: %s", label)
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s body: %w", name, err)
+	}
+
+	return xmlEncodingDecl.ReplaceAll(decoded, []byte("${1}UTF-8${3}")), nil
+}
+
+// newStreamingDecoder sets up an XML decoder that transcodes while reading.
+// An encoding declared in the prolog is handled by the decoder itself; the
+// Content-Type charset only applies when the prolog is silent.
+func newStreamingDecoder(r io.Reader, head []byte, contentType string) (*xml.Decoder, error) {
+	if xmlEncodingDecl.Find(head) == nil {
+		if _, params, err := mime.ParseMediaType(contentType); err == nil {
+			if label := params["charset"]; label != "" && !isUTF8Label(label) {
+				encoding, _ := charset.Lookup(label)
+				if encoding == nil {
+					return nil, fmt.Errorf("unsupported charset: %s", label)
+				}
+				r = transform.NewReader(r, encoding.NewDecoder())
+			}
+		}
+	}
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder, nil
+}
+
+func isUTF8Label(label string) bool {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return true
+	}
+	return false
+}
+
+func trimUTF8BOM(body []byte) []byte {
+	if len(body) >= len(utf8BOM) && string(body[:len(utf8BOM)]) == string(utf8BOM) {
+		return body[len(utf8BOM):]
+	}
+	return body
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"rsshub/internal/domain"
+
+	"golang.org/x/net/html"
+)
+
+var feedMediaTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/rdf+xml":   true,
+	"application/feed+json": true,
+}
+
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss",
+	"/feed.xml",
+	"/rss.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+// Discover resolves a page URL to feed URLs. A URL that already serves a
+// feed is returned as is; otherwise the page's alternate links are used,
+// falling back to probing well-known feed paths on the same host. The
+// request's credentials and headers are sent with every probe.
+func (f *RSSFetcher) Discover(ctx context.Context, pageReq *domain.FetchRequest) ([]string, error) {
+	pageURL := pageReq.URL
+	ctx, err := f.withProxy(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, finalURL, err := f.getDocument(ctx, pageReq, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parseFeed(body, contentType); err == nil {
+		return []string{pageURL}, nil
+	}
+
+	candidates, err := findAlternateLinks(body, finalURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+
+	for _, path := range commonFeedPaths {
+		candidate := finalURL.ResolveReference(&url.URL{Path: path}).String()
+		body, contentType, _, err := f.getDocument(ctx, pageReq, candidate)
+		if err != nil {
+			continue
+		}
+		if _, err := parseFeed(body, contentType); err == nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, domain.ErrNoFeedFound
+	}
+	return candidates, nil
+}
+
+func (f *RSSFetcher) getDocument(ctx context.Context, pageReq *domain.FetchRequest, rawURL string) ([]byte, string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := f.setRequestHeaders(req, pageReq); err != nil {
+		return nil, "", nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(newLimitedBody(resp.Body, f.maxBodyBytes))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return body, resp.Header.Get("Content-Type"), resp.Request.URL, nil
+}
+
+func findAlternateLinks(body []byte, pageURL *url.URL) ([]string, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML page: %w", err)
+	}
+
+	base := pageURL
+	var hrefs []string
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch node.Data {
+			case "base":
+				if href := attr(node, "href"); href != "" {
+					if parsed, err := pageURL.Parse(href); err == nil {
+						base = parsed
+					}
+				}
+			case "link":
+				if isFeedLink(node) {
+					hrefs = append(hrefs, attr(node, "href"))
+				}
+			case "body":
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, href := range hrefs {
+		resolved, err := base.Parse(href)
+		if err != nil {
+			continue
+		}
+		candidate := resolved.String()
+		if !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, nil
+}
+
+func isFeedLink(node *html.Node) bool {
+	if attr(node, "href") == "" {
+		return false
+	}
+
+	isAlternate := false
+	for _, rel := range strings.Fields(strings.ToLower(attr(node, "rel"))) {
+		if rel == "alternate" {
+			isAlternate = true
+		}
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(attr(node, "type")))
+	return isAlternate && feedMediaTypes[mediaType]
+}
+
+func attr(node *html.Node, key string) string {
+	for _, a := range node.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"time"
+
+	"rsshub/internal/domain"
+)
+
+const sniffLen = 1024
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// parseLimits bound how much of a feed is decoded. A zero value decodes
+// everything.
+type parseLimits struct {
+	maxItems int
+	since    *time.Time
+}
+
+func parseFeed(body []byte, contentType string) (*domain.ParsedFeed, error) {
+	return parseFeedStream(bytes.NewReader(body), contentType, parseLimits{})
+}
+
+// parseFeedStream decodes a feed while it is being read. XML feeds are
+// walked item by item, so once a limit is reached the rest of the body is
+// never read.
+func parseFeedStream(r io.Reader, contentType string, limits parseLimits) (*domain.ParsedFeed, error) {
+	reader := bufio.NewReaderSize(r, sniffLen)
+	head, _ := reader.Peek(sniffLen)
+	if bytes.HasPrefix(head, utf8BOM) {
+		reader.Discard(len(utf8BOM))
+		head = head[len(utf8BOM):]
+	}
+
+	if isJSONFeed(head, contentType) {
+		return parseJSONFeed(reader, limits)
+	}
+
+	return parseXMLFeed(reader, head, contentType, limits)
+}
+
+// parseJSONFeed decodes a JSON Feed token by token. The items array is read
+// one item at a time so the limits apply while reading, and a body that is
+// cut off or malformed inside the array keeps the items before the damage,
+// as XML feeds do.
+func parseJSONFeed(r io.Reader, limits parseLimits) (*domain.ParsedFeed, error) {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+
+	// Everything but the items is small, so it is collected as is and
+	// decoded into the feed at the end.
+	fields := make(map[string]json.RawMessage)
+	var items []domain.JSONFeedItem
+	limiter := itemLimiter{limits: limits}
+	itemsStarted := false
+
+	err := func() error {
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+
+			if key != "items" {
+				var value json.RawMessage
+				if err := decoder.Decode(&value); err != nil {
+					return err
+				}
+				fields[key] = value
+				continue
+			}
+
+			if err := expectDelim(decoder, '['); err != nil {
+				return err
+			}
+			itemsStarted = true
+			for decoder.More() {
+				if !limiter.admit() {
+					return nil
+				}
+				var item domain.JSONFeedItem
+				if err := decoder.Decode(&item); err != nil {
+					return err
+				}
+				items = append(items, item)
+				if limiter.add(item.PubDate()) {
+					return nil
+				}
+			}
+			if err := expectDelim(decoder, ']'); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil && !itemsStarted {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+
+	var jsonFeed domain.JSONFeed
+	head, _ := json.Marshal(fields)
+	if err := json.Unmarshal(head, &jsonFeed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+	jsonFeed.Items = items
+
+	parsed := jsonFeed.ToParsedFeed()
+	if err != nil {
+		parsed.Warnings = append(parsed.Warnings,
+			fmt.Sprintf("malformed JSON, salvaged %d items before: %v", len(parsed.Items), err))
+	}
+	limiter.report(parsed)
+
+	return parsed, nil
+}
+
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, found %v", want, token)
+	}
+	return nil
+}
+
+// itemLimiter decides when to stop decoding. Feeds list their newest items
+// first, so after a few consecutive items older than the newest stored
+// article the rest is already known; a short run is tolerated because
+// some feeds pin or slightly reorder entries.
+type itemLimiter struct {
+	limits       parseLimits
+	count        int
+	olderInARow  int
+	limitReached bool
+}
+
+const olderItemsBeforeStop = 3
+
+// admit is called before each item is decoded. Once maxItems items have
+// been taken it turns the next one away and records that the feed had more.
+func (l *itemLimiter) admit() bool {
+	if l.limits.maxItems > 0 && l.count >= l.limits.maxItems {
+		l.limitReached = true
+		return false
+	}
+	return true
+}
+
+// add counts a decoded item and reports whether the items after it are
+// older than the newest stored article, so decoding can stop.
+func (l *itemLimiter) add(pubDate string) bool {
+	l.count++
+
+	if l.limits.since == nil {
+		return false
+	}
+	if published := domain.ParseDate(pubDate); published != nil && published.Before(*l.limits.since) {
+		l.olderInARow++
+	} else {
+		l.olderInARow = 0
+	}
+	return l.olderInARow >= olderItemsBeforeStop
+}
+
+func (l *itemLimiter) report(parsed *domain.ParsedFeed) {
+	if l.limitReached {
+		parsed.Truncated = true
+		parsed.Warnings = append(parsed.Warnings,
+			fmt.Sprintf("item limit of %d reached, remaining items were skipped", l.limits.maxItems))
+	}
+}
+
+// isJSONFeed trusts an explicit JSON media type and otherwise sniffs the
+// body, since many servers label JSON Feed as text/plain or omit the header.
+func isJSONFeed(body []byte, contentType string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "application/feed+json", "application/json":
+			return true
+		}
+	}
+
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
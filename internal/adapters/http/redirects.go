@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"rsshub/internal/domain"
+)
+
+const maxRedirects = 10
+
+type redirectChainKey struct{}
+
+// withRedirectChain attaches a chain to ctx that recordRedirect fills in
+// while the client follows redirects for a request made with that context.
+func withRedirectChain(ctx context.Context) (context.Context, *[]domain.Redirect) {
+	chain := &[]domain.Redirect{}
+	return context.WithValue(ctx, redirectChainKey{}, chain), chain
+}
+
+// recordRedirect is the client's CheckRedirect hook. It keeps the default
+// limit of ten hops and appends each hop to the request's chain, if any.
+func recordRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	// The client already drops Authorization and Cookie when a redirect
+	// leaves the host; custom credential headers need the same treatment.
+	if req.URL.Hostname() != via[0].URL.Hostname() {
+		for name := range req.Header {
+			if domain.IsSensitiveHeader(name) {
+				req.Header.Del(name)
+			}
+		}
+	}
+
+	chain, ok := req.Context().Value(redirectChainKey{}).(*[]domain.Redirect)
+	if ok && req.Response != nil {
+		*chain = append(*chain, domain.Redirect{
+			URL:        req.URL.String(),
+			StatusCode: req.Response.StatusCode,
+		})
+	}
+	return nil
+}
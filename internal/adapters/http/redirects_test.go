@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRecordRedirectStripsCredentialHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantAPIKey string
+	}{
+		{"same host", "https://feeds.example.com/new.xml", "k3y"},
+		{"same host, other port", "https://feeds.example.com:8443/new.xml", "k3y"},
+		{"other host", "https://cdn.example.net/feed.xml", ""},
+		{"subdomain", "https://www.feeds.example.com/feed.xml", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := http.NewRequest(http.MethodGet, "https://feeds.example.com/feed.xml", nil)
+			if err != nil {
+				t.Fatalf("NewRequest: %v", err)
+			}
+			req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+			if err != nil {
+				t.Fatalf("NewRequest: %v", err)
+			}
+			req.Header.Set("X-Api-Key", "k3y")
+			req.Header.Set("Accept", "application/rss+xml")
+
+			if err := recordRedirect(req, []*http.Request{first}); err != nil {
+				t.Fatalf("recordRedirect: %v", err)
+			}
+			if got := req.Header.Get("X-Api-Key"); got != tt.wantAPIKey {
+				t.Fatalf("X-Api-Key = %q, want %q", got, tt.wantAPIKey)
+			}
+			if got := req.Header.Get("Accept"); got != "application/rss+xml" {
+				t.Fatalf("Accept = %q, want it kept", got)
+			}
+		})
+	}
+}
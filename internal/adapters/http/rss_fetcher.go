@@ -2,54 +2,184 @@ package http
 
 import (
 	"context"
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"rsshub/internal/domain"
+	"rsshub/internal/ports"
 )
 
 type RSSFetcher struct {
-	client *http.Client
+	client       *http.Client
+	secrets      ports.SecretCipher
+	maxBodyBytes int64
+	maxItems     int
 }
 
-func NewRSSFetcher() *RSSFetcher {
+// NewRSSFetcher returns a fetcher that connects as transport describes,
+// reads at most maxBodyBytes of each response and keeps at most maxItems
+// items per fetch. A zero maxItems keeps every item. Feed credentials are
+// decrypted with secrets.
+func NewRSSFetcher(transport TransportConfig, secrets ports.SecretCipher, maxBodyBytes int64, maxItems int) (*RSSFetcher, error) {
+	httpTransport, err := newTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+
 	return &RSSFetcher{
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport:     httpTransport,
+			Timeout:       30 * time.Second,
+			CheckRedirect: recordRedirect,
 		},
-	}
+		secrets:      secrets,
+		maxBodyBytes: maxBodyBytes,
+		maxItems:     maxItems,
+	}, nil
 }
 
-func (f *RSSFetcher) Fetch(ctx context.Context, url string) (*domain.RSSFeed, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func (f *RSSFetcher) Fetch(ctx context.Context, fetchReq *domain.FetchRequest) (*domain.FetchResult, error) {
+	ctx, err := f.withProxy(ctx, fetchReq)
+	if err != nil {
+		return nil, err
+	}
+	ctx, redirects := withRedirectChain(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchReq.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "RSSHub/1.0")
+	if err := f.setRequestHeaders(req, fetchReq); err != nil {
+		return nil, err
+	}
+	if fetchReq.ETag != "" {
+		req.Header.Set("If-None-Match", fetchReq.ETag)
+	}
+	if fetchReq.LastModified != "" {
+		req.Header.Set("If-Modified-Since", fetchReq.LastModified)
+	}
 
 	resp, err := f.client.Do(req)
 	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, fmt.Errorf("failed to fetch RSS feed: %w: %w", domain.ErrHostNotFound, err)
+		}
 		return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		result := &domain.FetchResult{
+			StatusCode:   resp.StatusCode,
+			NotModified:  true,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Redirects:    *redirects,
+		}
+		if result.ETag == "" {
+			result.ETag = fetchReq.ETag
+		}
+		if result.LastModified == "" {
+			result.LastModified = fetchReq.LastModified
+		}
+		return result, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &domain.StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body := newLimitedBody(resp.Body, f.maxBodyBytes)
+	limits := parseLimits{maxItems: f.maxItems, since: fetchReq.Since}
+
+	parsedFeed, err := parseFeedStream(body, resp.Header.Get("Content-Type"), limits)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
+	body.report(parsedFeed)
+
+	return &domain.FetchResult{
+		Feed:         parsedFeed,
+		StatusCode:   resp.StatusCode,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		BytesRead:    body.read,
+		Redirects:    *redirects,
+	}, nil
+}
 
-	var rssFeed domain.RSSFeed
-	if err := xml.Unmarshal(body, &rssFeed); err != nil {
-		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+// withProxy decrypts the feed's proxy URL and makes requests sent with the
+// returned context use it.
+func (f *RSSFetcher) withProxy(ctx context.Context, fetchReq *domain.FetchRequest) (context.Context, error) {
+	proxyURL := fetchReq.ProxyURL
+	if proxyURL == "" || proxyURL == domain.ProxyDirect {
+		return withFeedProxy(ctx, proxyURL), nil
+	}
+	proxyURL, err := f.secrets.Decrypt(proxyURL, domain.SecretScope(fetchReq.FeedID, domain.SecretColumnProxy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt proxy URL: %w", err)
+	}
+	return withFeedProxy(ctx, proxyURL), nil
+}
+
+// setRequestHeaders applies the feed's custom headers over the defaults,
+// then its credentials. Secrets among them are only decrypted here. The
+// client drops Authorization and Cookie itself when a redirect leaves the
+// original host.
+func (f *RSSFetcher) setRequestHeaders(req *http.Request, fetchReq *domain.FetchRequest) error {
+	req.Header.Set("User-Agent", "RSSHub/1.0")
+	for name, value := range fetchReq.Headers {
+		if domain.IsSensitiveHeader(name) {
+			var err error
+			value, err = f.secrets.Decrypt(value, domain.HeaderSecretScope(fetchReq.FeedID, name))
+			if err != nil {
+				return fmt.Errorf("failed to decrypt header %s: %w", name, err)
+			}
+		}
+		req.Header.Set(name, value)
 	}
 
-	return &rssFeed, nil
+	auth := fetchReq.Auth
+	if auth.IsZero() {
+		return nil
+	}
+	secret, err := f.secrets.Decrypt(auth.Secret, domain.SecretScope(fetchReq.FeedID, domain.SecretColumnAuth))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt feed credentials: %w", err)
+	}
+
+	switch auth.Type {
+	case domain.AuthBasic:
+		req.SetBasicAuth(auth.Username, secret)
+	case domain.AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+secret)
+	case domain.AuthCookie:
+		req.Header.Set("Cookie", secret)
+	}
+	return nil
+}
+
+// parseRetryAfter reads a Retry-After header given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
 }
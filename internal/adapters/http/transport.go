@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"rsshub/internal/domain"
+)
+
+// TransportConfig describes how the fetcher reaches the network. Every
+// field is optional: without a ProxyURL the standard HTTP_PROXY variables
+// apply, CABundlePath adds to the system roots rather than replacing them,
+// and a client certificate needs both its cert and key paths.
+type TransportConfig struct {
+	ProxyURL       string
+	CABundlePath   string
+	ClientCertPath string
+	ClientKeyPath  string
+	MinTLSVersion  string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+type feedProxyKey struct{}
+
+// withFeedProxy makes requests sent with ctx use the feed's own proxy, or
+// none at all for domain.ProxyDirect, instead of the configured one.
+func withFeedProxy(ctx context.Context, proxyURL string) context.Context {
+	if proxyURL == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, feedProxyKey{}, proxyURL)
+}
+
+func newTransport(cfg TransportConfig) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultProxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		if err := domain.ValidateProxyURL(cfg.ProxyURL); err != nil {
+			return nil, err
+		}
+		proxyURL, _ := url.Parse(cfg.ProxyURL)
+		defaultProxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		override, ok := req.Context().Value(feedProxyKey{}).(string)
+		if !ok {
+			return defaultProxy(req)
+		}
+		if override == domain.ProxyDirect {
+			return nil, nil
+		}
+		return url.Parse(override)
+	}
+	return transport, nil
+}
+
+func newTLSConfig(cfg TransportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.MinTLSVersion != "" {
+		version, ok := tlsVersions[cfg.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", cfg.MinTLSVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.CABundlePath != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle, err := os.ReadFile(cfg.CABundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundlePath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertPath != "" || cfg.ClientKeyPath != "" {
+		if cfg.ClientCertPath == "" || cfg.ClientKeyPath == "" {
+			return nil, fmt.Errorf("client certificate and key must be configured together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+
+	"rsshub/internal/domain"
+)
+
+// voidHTMLElements mirrors xml.HTMLAutoClose without "link", which is a
+// regular element with content in RSS.
+var voidHTMLElements = []string{
+	"basefont", "br", "area", "img", "param", "hr", "input", "col", "frame", "isindex", "base", "meta",
+}
+
+var (
+	cdataStart  = []byte("<![CDATA[")
+	cdataEnd    = []byte("]]>")
+	openingTag  = regexp.MustCompile(`<([A-Za-z_][\w:.-]*)[^<>]*>\s*$`)
+	maxRefChars = 32
+)
+
+// maxRecoveryBytes bounds how much of a body is kept for the lenient pass.
+// Larger documents that fail the strict pass keep the items read before
+// the error instead.
+const maxRecoveryBytes = 1 << 20
+
+// recoveryBuffer keeps a copy of the body while it is at most
+// maxRecoveryBytes long and drops it once it grows past that.
+type recoveryBuffer struct {
+	buf        bytes.Buffer
+	overflowed bool
+}
+
+func (b *recoveryBuffer) Write(p []byte) (int, error) {
+	if b.overflowed {
+		return len(p), nil
+	}
+	if b.buf.Len()+len(p) > maxRecoveryBytes {
+		b.overflowed = true
+		b.buf = bytes.Buffer{}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// parseXMLFeed decodes strictly while streaming first. Documents that fail
+// are read to the end, cleaned of the usual breakage and decoded again in
+// non-strict mode with HTML entities allowed; if even that stops early, or
+// the document is too large to decode twice, the items read before the
+// error are kept and the problem is reported as a warning.
+func parseXMLFeed(r io.Reader, head []byte, contentType string, limits parseLimits) (*domain.ParsedFeed, error) {
+	raw := &recoveryBuffer{}
+	decoder, err := newStreamingDecoder(io.TeeReader(r, raw), head, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	strict := newXMLFeedWalker(limits)
+	strictErr := strict.walk(decoder)
+	if strictErr == nil {
+		return strict.parsedFeed(), nil
+	}
+
+	if !raw.overflowed {
+		if _, err := io.Copy(raw, r); err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+	}
+	if raw.overflowed {
+		return salvageXMLFeed(strict, strictErr, strictErr)
+	}
+	body, err := toUTF8(raw.buf.Bytes(), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	lenient := newXMLFeedWalker(limits)
+	decoder = xml.NewDecoder(bytes.NewReader(cleanXML(body)))
+	decoder.Strict = false
+	decoder.AutoClose = voidHTMLElements
+	decoder.Entity = xml.HTMLEntity
+
+	if err := lenient.walk(decoder); err != nil {
+		return salvageXMLFeed(lenient, err, strictErr)
+	}
+
+	parsed := lenient.parsedFeed()
+	parsed.Warnings = append(parsed.Warnings, fmt.Sprintf("malformed XML recovered: %v", strictErr))
+	return parsed, nil
+}
+
+// salvageXMLFeed keeps the items walker read before stopErr stopped it. A
+// walker that read none fails with strictErr, the error of the strict pass.
+func salvageXMLFeed(walker *xmlFeedWalker, stopErr, strictErr error) (*domain.ParsedFeed, error) {
+	if walker.root == "" || walker.itemCount() == 0 {
+		return nil, fmt.Errorf("failed to parse feed: %w", strictErr)
+	}
+	parsed := walker.parsedFeed()
+	parsed.Warnings = append(parsed.Warnings,
+		fmt.Sprintf("malformed XML, salvaged %d items before: %v", len(parsed.Items), stopErr))
+	return parsed, nil
+}
+
+// cleanXML repairs the breakage most often seen in published feeds:
+// invalid UTF-8 and control characters, bare ampersands and CDATA sections
+// that are never closed.
+func cleanXML(body []byte) []byte {
+	body = bytes.ToValidUTF8(body, []byte("�"))
+	body = stripControlChars(body)
+	body = escapeBareAmpersands(body)
+	return closeCDATA(body)
+}
+
+func stripControlChars(body []byte) []byte {
+	return bytes.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		if r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, body)
+}
+
+// escapeBareAmpersands rewrites "&" as "&amp;" unless it starts an entity
+// or character reference. CDATA sections are left untouched.
+func escapeBareAmpersands(body []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(body))
+
+	for i := 0; i < len(body); {
+		if bytes.HasPrefix(body[i:], cdataStart) {
+			end := bytes.Index(body[i+len(cdataStart):], cdataEnd)
+			if end < 0 {
+				out.Write(body[i:])
+				break
+			}
+			next := i + len(cdataStart) + end + len(cdataEnd)
+			out.Write(body[i:next])
+			i = next
+			continue
+		}
+
+		if body[i] == '&' && !isReference(body[i+1:]) {
+			out.WriteString("&amp;")
+			i++
+			continue
+		}
+
+		out.WriteByte(body[i])
+		i++
+	}
+
+	return out.Bytes()
+}
+
+func isReference(rest []byte) bool {
+	end := bytes.IndexByte(rest, ';')
+	if end <= 0 || end > maxRefChars {
+		return false
+	}
+
+	name := rest[:end]
+	if name[0] == '#' {
+		digits := name[1:]
+		hex := len(digits) > 0 && (digits[0] == 'x' || digits[0] == 'X')
+		if hex {
+			digits = digits[1:]
+		}
+		if len(digits) == 0 {
+			return false
+		}
+		for _, c := range digits {
+			isDigit := c >= '0' && c <= '9'
+			isHex := (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+			if !isDigit && !(hex && isHex) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, r := range string(name) {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(i > 0 && isDigit) {
+			return false
+		}
+	}
+	return true
+}
+
+// closeCDATA terminates CDATA sections that run into the next section or
+// the end of the document. The terminator is placed before the end tag of
+// the element that opened the section, which is where the publisher meant
+// it to be.
+func closeCDATA(body []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(body))
+
+	for {
+		start := bytes.Index(body, cdataStart)
+		if start < 0 {
+			out.Write(body)
+			return out.Bytes()
+		}
+
+		contentStart := start + len(cdataStart)
+		end := bytes.Index(body[contentStart:], cdataEnd)
+		nextStart := bytes.Index(body[contentStart:], cdataStart)
+		if end >= 0 && (nextStart < 0 || end < nextStart) {
+			next := contentStart + end + len(cdataEnd)
+			out.Write(body[:next])
+			body = body[next:]
+			continue
+		}
+
+		limit := len(body)
+		if nextStart >= 0 {
+			limit = contentStart + nextStart
+		}
+
+		closeAt := limit
+		if match := openingTag.FindSubmatch(body[:start]); match != nil {
+			endTag := []byte("</" + string(match[1]))
+			if i := bytes.Index(body[contentStart:limit], endTag); i >= 0 {
+				closeAt = contentStart + i
+			}
+		}
+
+		out.Write(body[:closeAt])
+		out.Write(cdataEnd)
+		body = body[closeAt:]
+	}
+}
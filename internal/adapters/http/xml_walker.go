@@ -0,0 +1,246 @@
+package http
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"rsshub/internal/domain"
+)
+
+// xmlFeedWalker reads an XML feed token by token and decodes every item
+// on its own. Unlike a single Unmarshal of the whole document, a syntax
+// error only loses the items after it, so the caller can keep what was
+// read so far, and reading stops as soon as the limiter is satisfied.
+type xmlFeedWalker struct {
+	root    string
+	rss     domain.RSSFeed
+	atom    domain.AtomFeed
+	rdf     domain.RDFFeed
+	limiter itemLimiter
+	stopped bool
+}
+
+func newXMLFeedWalker(limits parseLimits) *xmlFeedWalker {
+	return &xmlFeedWalker{limiter: itemLimiter{limits: limits}}
+}
+
+func (w *xmlFeedWalker) walk(decoder *xml.Decoder) error {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if w.root == "" {
+				return fmt.Errorf("document has no root element")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				if err := w.setRoot(t.Name.Local); err != nil {
+					return err
+				}
+				continue
+			}
+
+			consumed, err := w.decodeElement(decoder, t, depth)
+			if err != nil {
+				return err
+			}
+			if w.stopped {
+				return nil
+			}
+			if consumed {
+				depth--
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+func (w *xmlFeedWalker) setRoot(root string) error {
+	switch root {
+	case "rss", "feed", "RDF":
+		w.root = root
+		return nil
+	default:
+		return fmt.Errorf("unsupported feed format: <%s>", root)
+	}
+}
+
+// decodeElement handles the start of an element below the root. It reports
+// whether the whole element, including its end tag, has been consumed.
+func (w *xmlFeedWalker) decodeElement(decoder *xml.Decoder, start xml.StartElement, depth int) (bool, error) {
+	switch w.root {
+	case "rss":
+		return w.decodeRSSElement(decoder, start, depth)
+	case "feed":
+		return w.decodeAtomElement(decoder, start, depth)
+	default:
+		return w.decodeRDFElement(decoder, start, depth)
+	}
+}
+
+func (w *xmlFeedWalker) decodeRSSElement(decoder *xml.Decoder, start xml.StartElement, depth int) (bool, error) {
+	if depth == 2 && start.Name.Local == "channel" {
+		return false, nil
+	}
+	if depth != 3 {
+		return true, decoder.Skip()
+	}
+
+	channel := &w.rss.Channel
+	if start.Name.Space == domain.SyndicationNS {
+		return true, decodeSyndicationElement(decoder, start, &channel.SyndicationElements)
+	}
+	// Other namespaced elements such as atom:link share local names with
+	// the plain RSS ones and must not overwrite them.
+	if start.Name.Space != "" {
+		return true, decoder.Skip()
+	}
+
+	switch start.Name.Local {
+	case "item":
+		if !w.limiter.admit() {
+			w.stopped = true
+			return true, nil
+		}
+		var item domain.RSSItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return true, err
+		}
+		channel.Items = append(channel.Items, item)
+		w.stopped = w.limiter.add(item.PubDate)
+		return true, nil
+	case "title":
+		return true, decoder.DecodeElement(&channel.Title, &start)
+	case "link":
+		return true, decoder.DecodeElement(&channel.Link, &start)
+	case "description":
+		return true, decoder.DecodeElement(&channel.Description, &start)
+	case "ttl":
+		return true, decoder.DecodeElement(&channel.TTL, &start)
+	case "skipHours":
+		return true, decoder.DecodeElement(&channel.SkipHours, &start)
+	case "skipDays":
+		return true, decoder.DecodeElement(&channel.SkipDays, &start)
+	default:
+		return true, decoder.Skip()
+	}
+}
+
+func (w *xmlFeedWalker) decodeAtomElement(decoder *xml.Decoder, start xml.StartElement, depth int) (bool, error) {
+	if depth != 2 {
+		return true, decoder.Skip()
+	}
+
+	feed := &w.atom
+	if start.Name.Space == domain.SyndicationNS {
+		return true, decodeSyndicationElement(decoder, start, &feed.SyndicationElements)
+	}
+
+	switch start.Name.Local {
+	case "entry":
+		if !w.limiter.admit() {
+			w.stopped = true
+			return true, nil
+		}
+		var entry domain.AtomEntry
+		if err := decoder.DecodeElement(&entry, &start); err != nil {
+			return true, err
+		}
+		feed.Entries = append(feed.Entries, entry)
+		w.stopped = w.limiter.add(entry.PubDate())
+		return true, nil
+	case "title":
+		return true, decoder.DecodeElement(&feed.Title, &start)
+	case "subtitle":
+		return true, decoder.DecodeElement(&feed.Subtitle, &start)
+	case "updated":
+		return true, decoder.DecodeElement(&feed.Updated, &start)
+	case "link":
+		var link domain.AtomLink
+		if err := decoder.DecodeElement(&link, &start); err != nil {
+			return true, err
+		}
+		feed.Links = append(feed.Links, link)
+		return true, nil
+	case "author":
+		var author domain.AtomPerson
+		if err := decoder.DecodeElement(&author, &start); err != nil {
+			return true, err
+		}
+		feed.Authors = append(feed.Authors, author)
+		return true, nil
+	default:
+		return true, decoder.Skip()
+	}
+}
+
+func (w *xmlFeedWalker) decodeRDFElement(decoder *xml.Decoder, start xml.StartElement, depth int) (bool, error) {
+	if depth != 2 {
+		return true, decoder.Skip()
+	}
+
+	switch start.Name.Local {
+	case "item":
+		if !w.limiter.admit() {
+			w.stopped = true
+			return true, nil
+		}
+		var item domain.RDFItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return true, err
+		}
+		w.rdf.Items = append(w.rdf.Items, item)
+		w.stopped = w.limiter.add(item.Date)
+		return true, nil
+	case "channel":
+		return true, decoder.DecodeElement(&w.rdf.Channel, &start)
+	default:
+		return true, decoder.Skip()
+	}
+}
+
+func decodeSyndicationElement(decoder *xml.Decoder, start xml.StartElement, sy *domain.SyndicationElements) error {
+	switch start.Name.Local {
+	case "updatePeriod":
+		return decoder.DecodeElement(&sy.UpdatePeriod, &start)
+	case "updateFrequency":
+		return decoder.DecodeElement(&sy.UpdateFrequency, &start)
+	default:
+		return decoder.Skip()
+	}
+}
+
+func (w *xmlFeedWalker) itemCount() int {
+	switch w.root {
+	case "rss":
+		return len(w.rss.Channel.Items)
+	case "feed":
+		return len(w.atom.Entries)
+	default:
+		return len(w.rdf.Items)
+	}
+}
+
+func (w *xmlFeedWalker) parsedFeed() *domain.ParsedFeed {
+	var parsed *domain.ParsedFeed
+	switch w.root {
+	case "rss":
+		parsed = w.rss.ToParsedFeed()
+	case "feed":
+		parsed = w.atom.ToParsedFeed()
+	default:
+		parsed = w.rdf.ToParsedFeed()
+	}
+	w.limiter.report(parsed)
+	return parsed
+}
@@ -2,13 +2,31 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"rsshub/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const insertArticleQuery = `
+	INSERT INTO articles (id, created_at, updated_at, title, link, published_at, description, feed_id,
+		guid, author, content, categories, dedup_key, fingerprint, story_id, published_at_inferred)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	ON CONFLICT (feed_id, dedup_key) DO NOTHING
+`
+
+const insertEnclosureQuery = `
+	INSERT INTO article_enclosures (article_id, url, mime_type, length, duration_seconds, thumbnail_url)
+	VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+const articleColumns = `a.id, a.created_at, a.updated_at, a.title, a.link, a.published_at, a.description, a.feed_id,
+	a.guid, a.author, a.content, a.categories, a.dedup_key, a.fingerprint, a.story_id, a.published_at_inferred`
+
 type ArticleRepository struct {
 	db *DB
 }
@@ -18,19 +36,7 @@ func NewArticleRepository(db *DB) *ArticleRepository {
 }
 
 func (r *ArticleRepository) Create(ctx context.Context, article *domain.Article) error {
-	query := `
-		INSERT INTO articles (id, created_at, updated_at, title, link, published_at, description, feed_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		ON CONFLICT (link, feed_id) DO NOTHING
-	`
-	_, err := r.db.conn.ExecContext(ctx, query,
-		article.ID, article.CreatedAt, article.UpdatedAt,
-		article.Title, article.Link, article.PublishedAt,
-		article.Description, article.FeedID)
-	if err != nil {
-		return fmt.Errorf("failed to create article: %w", err)
-	}
-	return nil
+	return r.CreateBatch(ctx, []*domain.Article{article})
 }
 
 func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*domain.Article) error {
@@ -44,24 +50,42 @@ func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*domain.
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO articles (id, created_at, updated_at, title, link, published_at, description, feed_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		ON CONFLICT (link, feed_id) DO NOTHING
-	`)
+	stmt, err := tx.PrepareContext(ctx, insertArticleQuery)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
+	enclosureStmt, err := tx.PrepareContext(ctx, insertEnclosureQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare enclosure statement: %w", err)
+	}
+	defer enclosureStmt.Close()
+
 	for _, article := range articles {
-		_, err := stmt.ExecContext(ctx,
-			article.ID, article.CreatedAt, article.UpdatedAt,
-			article.Title, article.Link, article.PublishedAt,
-			article.Description, article.FeedID)
+		result, err := stmt.ExecContext(ctx, articleArgs(article)...)
 		if err != nil {
 			return fmt.Errorf("failed to insert article: %w", err)
 		}
+
+		// A conflicting article is skipped, and so are its enclosures,
+		// which would otherwise reference a row that was never written.
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if inserted == 0 {
+			continue
+		}
+
+		for _, enclosure := range article.Enclosures {
+			_, err := enclosureStmt.ExecContext(ctx,
+				article.ID, enclosure.URL, enclosure.MimeType, enclosure.Length,
+				int64(enclosure.Duration.Seconds()), enclosure.ThumbnailURL)
+			if err != nil {
+				return fmt.Errorf("failed to insert enclosure: %w", err)
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -73,7 +97,7 @@ func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*domain.
 
 func (r *ArticleRepository) GetByFeedName(ctx context.Context, feedName string, limit int) ([]*domain.Article, error) {
 	query := `
-		SELECT a.id, a.created_at, a.updated_at, a.title, a.link, a.published_at, a.description, a.feed_id
+		SELECT ` + articleColumns + `
 		FROM articles a
 		INNER JOIN feeds f ON a.feed_id = f.id
 		WHERE f.name = $1
@@ -86,28 +110,276 @@ func (r *ArticleRepository) GetByFeedName(ctx context.Context, feedName string,
 	}
 	defer rows.Close()
 
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	return articles, r.loadEnclosures(ctx, articles)
+}
+
+// GetByCategory returns articles tagged with category. An empty feedName
+// searches across all feeds.
+func (r *ArticleRepository) GetByCategory(ctx context.Context, feedName, category string, limit int) ([]*domain.Article, error) {
+	query := `
+		SELECT ` + articleColumns + `
+		FROM articles a
+		INNER JOIN feeds f ON a.feed_id = f.id
+		WHERE a.categories @> ARRAY[$1]::TEXT[]
+		AND ($2 = '' OR f.name = $2)
+		ORDER BY COALESCE(a.published_at, a.created_at) DESC
+		LIMIT $3
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, category, feedName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by category: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	return articles, r.loadEnclosures(ctx, articles)
+}
+
+// Exists reports whether the feed already has an article with dedupKey.
+// An article stored without a guid also matches by linkKey, since rows
+// saved before guids were kept carry a link key even when the item has a
+// guid today.
+func (r *ArticleRepository) Exists(ctx context.Context, dedupKey, linkKey string, feedID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM articles
+			WHERE feed_id = $3
+				AND (dedup_key = $1 OR ($2 <> '' AND guid = '' AND dedup_key = $2))
+		)
+	`
+	var exists bool
+	err := r.db.conn.QueryRowContext(ctx, query, dedupKey, linkKey, feedID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check article existence: %w", err)
+	}
+	return exists, nil
+}
+
+// GetLatestPublishedAt returns the publication time of the newest stored
+// article of a feed, or nil when the feed has no dated articles yet.
+// Inferred dates are ignored since they say nothing about the feed's order.
+func (r *ArticleRepository) GetLatestPublishedAt(ctx context.Context, feedID uuid.UUID) (*time.Time, error) {
+	query := `SELECT MAX(published_at) FROM articles WHERE feed_id = $1 AND NOT published_at_inferred`
+	var latest sql.NullTime
+	if err := r.db.conn.QueryRowContext(ctx, query, feedID).Scan(&latest); err != nil {
+		return nil, fmt.Errorf("failed to get latest article date: %w", err)
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
+
+// ListNonCanonicalKeys returns up to limit articles of a feed whose link
+// keys predate canonicalization, with only their ID, link and key set.
+func (r *ArticleRepository) ListNonCanonicalKeys(ctx context.Context, feedID uuid.UUID, limit int) ([]*domain.Article, error) {
+	query := `
+		SELECT id, link, dedup_key
+		FROM articles
+		WHERE feed_id = $1 AND NOT dedup_key_canonical
+		LIMIT $2
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, feedID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list non-canonical dedup keys: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []*domain.Article
+	for rows.Next() {
+		article := &domain.Article{FeedID: feedID}
+		if err := rows.Scan(&article.ID, &article.Link, &article.DedupKey); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
+// UpdateDedupKeys stores the canonical dedup keys of articles, keyed by
+// article ID, in one statement. When another article of the feed already
+// has a key the two are duplicates from before canonicalization; both are
+// kept and the updated one keeps its old key. Callers must not assign one
+// key to several articles of a feed.
+func (r *ArticleRepository) UpdateDedupKeys(ctx context.Context, dedupKeys map[uuid.UUID]string) error {
+	if len(dedupKeys) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(dedupKeys))
+	keys := make([]string, 0, len(dedupKeys))
+	for id, key := range dedupKeys {
+		ids = append(ids, id.String())
+		keys = append(keys, key)
+	}
+
+	query := `
+		UPDATE articles a
+		SET
+			dedup_key = CASE
+				WHEN EXISTS (
+					SELECT 1 FROM articles b
+					WHERE b.feed_id = a.feed_id AND b.dedup_key = u.dedup_key AND b.id <> a.id
+				) THEN a.dedup_key
+				ELSE u.dedup_key
+			END,
+			dedup_key_canonical = TRUE
+		FROM unnest($1::UUID[], $2::TEXT[]) AS u (id, dedup_key)
+		WHERE a.id = u.id
+	`
+	if _, err := r.db.conn.ExecContext(ctx, query, pq.Array(ids), pq.Array(keys)); err != nil {
+		return fmt.Errorf("failed to update dedup keys: %w", err)
+	}
+	return nil
+}
+
+func (r *ArticleRepository) GetRecentFingerprints(ctx context.Context, since time.Time) ([]domain.StoryFingerprint, error) {
+	query := `
+		SELECT id, feed_id, story_id, fingerprint
+		FROM articles
+		WHERE fingerprint <> 0
+		AND COALESCE(published_at, created_at) >= $1
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	var fingerprints []domain.StoryFingerprint
+	for rows.Next() {
+		var fp domain.StoryFingerprint
+		var fingerprint int64
+		if err := rows.Scan(&fp.ArticleID, &fp.FeedID, &fp.StoryID, &fingerprint); err != nil {
+			return nil, fmt.Errorf("failed to scan fingerprint: %w", err)
+		}
+		fp.Fingerprint = uint64(fingerprint)
+		fingerprints = append(fingerprints, fp)
+	}
+
+	return fingerprints, rows.Err()
+}
+
+// GetStories returns the limit most recently updated stories with all of
+// their sources, earliest source first.
+func (r *ArticleRepository) GetStories(ctx context.Context, limit int) ([]*domain.Story, error) {
+	query := `
+		WITH latest AS (
+			SELECT story_id, MAX(COALESCE(published_at, created_at)) AS latest_at
+			FROM articles
+			GROUP BY story_id
+			ORDER BY latest_at DESC
+			LIMIT $1
+		)
+		SELECT a.story_id, a.id, f.name, a.title, a.link, a.published_at, a.created_at
+		FROM articles a
+		INNER JOIN latest l ON a.story_id = l.story_id
+		INNER JOIN feeds f ON a.feed_id = f.id
+		ORDER BY l.latest_at DESC, a.story_id, COALESCE(a.published_at, a.created_at) ASC
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stories: %w", err)
+	}
+	defer rows.Close()
+
+	var stories []*domain.Story
+	var current *domain.Story
+	for rows.Next() {
+		var storyID uuid.UUID
+		var source domain.StorySource
+		err := rows.Scan(&storyID, &source.ArticleID, &source.FeedName, &source.Title,
+			&source.Link, &source.PublishedAt, &source.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan story source: %w", err)
+		}
+
+		if current == nil || current.ID != storyID {
+			current = &domain.Story{ID: storyID}
+			stories = append(stories, current)
+		}
+		current.Sources = append(current.Sources, source)
+	}
+
+	return stories, rows.Err()
+}
+
+func (r *ArticleRepository) loadEnclosures(ctx context.Context, articles []*domain.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Article, len(articles))
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+		ids = append(ids, article.ID.String())
+	}
+
+	query := `
+		SELECT article_id, url, mime_type, length, duration_seconds, thumbnail_url
+		FROM article_enclosures
+		WHERE article_id = ANY($1::UUID[])
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get enclosures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID uuid.UUID
+		var enclosure domain.Enclosure
+		var durationSeconds int64
+		err := rows.Scan(&articleID, &enclosure.URL, &enclosure.MimeType, &enclosure.Length,
+			&durationSeconds, &enclosure.ThumbnailURL)
+		if err != nil {
+			return fmt.Errorf("failed to scan enclosure: %w", err)
+		}
+		enclosure.Duration = time.Duration(durationSeconds) * time.Second
+		if article, ok := byID[articleID]; ok {
+			article.Enclosures = append(article.Enclosures, enclosure)
+		}
+	}
+
+	return rows.Err()
+}
+
+func articleArgs(article *domain.Article) []any {
+	return []any{
+		article.ID, article.CreatedAt, article.UpdatedAt,
+		article.Title, article.Link, article.PublishedAt,
+		article.Description, article.FeedID,
+		article.GUID, article.Author, article.Content, pq.Array(nonNilStrings(article.Categories)),
+		article.DedupKey, int64(article.Fingerprint), article.StoryID, article.PublishedAtInferred,
+	}
+}
+
+func scanArticles(rows *sql.Rows) ([]*domain.Article, error) {
 	var articles []*domain.Article
 	for rows.Next() {
 		article := &domain.Article{}
+		var fingerprint int64
 		err := rows.Scan(
 			&article.ID, &article.CreatedAt, &article.UpdatedAt,
 			&article.Title, &article.Link, &article.PublishedAt,
-			&article.Description, &article.FeedID)
+			&article.Description, &article.FeedID,
+			&article.GUID, &article.Author, &article.Content, pq.Array(&article.Categories),
+			&article.DedupKey, &fingerprint, &article.StoryID, &article.PublishedAtInferred)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
+		article.Fingerprint = uint64(fingerprint)
 		articles = append(articles, article)
 	}
 
 	return articles, rows.Err()
 }
-
-func (r *ArticleRepository) Exists(ctx context.Context, link string, feedID uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM articles WHERE link = $1 AND feed_id = $2)`
-	var exists bool
-	err := r.db.conn.QueryRowContext(ctx, query, link, feedID).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("failed to check article existence: %w", err)
-	}
-	return exists, nil
-}
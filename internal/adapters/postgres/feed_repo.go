@@ -3,13 +3,22 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"rsshub/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const feedColumns = `id, created_at, updated_at, name, url, last_fetched_at, etag, last_modified, strip_params,
+	redirect_url, redirect_count, status, not_found_count,
+	consecutive_failures, last_error, last_error_at, next_fetch_at,
+	ttl_seconds, update_period_seconds, skip_hours, skip_days, interval_override_seconds,
+	auth_type, auth_username, auth_secret, headers, proxy_url`
+
 type FeedRepository struct {
 	db *DB
 }
@@ -19,12 +28,19 @@ func NewFeedRepository(db *DB) *FeedRepository {
 }
 
 func (r *FeedRepository) Create(ctx context.Context, feed *domain.Feed) error {
+	headers, err := marshalHeaders(feed.Headers)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO feeds (id, created_at, updated_at, name, url)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO feeds (id, created_at, updated_at, name, url, strip_params, status,
+			auth_type, auth_username, auth_secret, headers, proxy_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	_, err := r.db.conn.ExecContext(ctx, query,
-		feed.ID, feed.CreatedAt, feed.UpdatedAt, feed.Name, feed.URL)
+	_, err = r.db.conn.ExecContext(ctx, query,
+		feed.ID, feed.CreatedAt, feed.UpdatedAt, feed.Name, feed.URL, pq.Array(nonNilStrings(feed.StripParams)),
+		feed.Status, string(feed.Auth.Type), feed.Auth.Username, feed.Auth.Secret, headers, feed.ProxyURL)
 	if err != nil {
 		return fmt.Errorf("failed to create feed: %w", err)
 	}
@@ -32,13 +48,8 @@ func (r *FeedRepository) Create(ctx context.Context, feed *domain.Feed) error {
 }
 
 func (r *FeedRepository) GetByName(ctx context.Context, name string) (*domain.Feed, error) {
-	query := `
-		SELECT id, created_at, updated_at, name, url, last_fetched_at
-		FROM feeds WHERE name = $1
-	`
-	feed := &domain.Feed{}
-	err := r.db.conn.QueryRowContext(ctx, query, name).Scan(
-		&feed.ID, &feed.CreatedAt, &feed.UpdatedAt, &feed.Name, &feed.URL, &feed.LastFetchedAt)
+	query := `SELECT ` + feedColumns + ` FROM feeds WHERE name = $1`
+	feed, err := scanFeed(r.db.conn.QueryRowContext(ctx, query, name))
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -49,13 +60,8 @@ func (r *FeedRepository) GetByName(ctx context.Context, name string) (*domain.Fe
 }
 
 func (r *FeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Feed, error) {
-	query := `
-		SELECT id, created_at, updated_at, name, url, last_fetched_at
-		FROM feeds WHERE id = $1
-	`
-	feed := &domain.Feed{}
-	err := r.db.conn.QueryRowContext(ctx, query, id).Scan(
-		&feed.ID, &feed.CreatedAt, &feed.UpdatedAt, &feed.Name, &feed.URL, &feed.LastFetchedAt)
+	query := `SELECT ` + feedColumns + ` FROM feeds WHERE id = $1`
+	feed, err := scanFeed(r.db.conn.QueryRowContext(ctx, query, id))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feed: %w", err)
 	}
@@ -64,7 +70,7 @@ func (r *FeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Fee
 
 func (r *FeedRepository) List(ctx context.Context, limit int) ([]*domain.Feed, error) {
 	query := `
-		SELECT id, created_at, updated_at, name, url, last_fetched_at
+		SELECT ` + feedColumns + `
 		FROM feeds ORDER BY created_at DESC LIMIT $1
 	`
 	rows, err := r.db.conn.QueryContext(ctx, query, limit)
@@ -78,7 +84,7 @@ func (r *FeedRepository) List(ctx context.Context, limit int) ([]*domain.Feed, e
 
 func (r *FeedRepository) ListAll(ctx context.Context) ([]*domain.Feed, error) {
 	query := `
-		SELECT id, created_at, updated_at, name, url, last_fetched_at
+		SELECT ` + feedColumns + `
 		FROM feeds ORDER BY created_at DESC
 	`
 	rows, err := r.db.conn.QueryContext(ctx, query)
@@ -108,27 +114,158 @@ func (r *FeedRepository) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+// Update saves the feed. When its URL changed, the previous one is kept in
+// feed_url_history. A pause set by an operator while the feed was being
+// fetched is left in place.
 func (r *FeedRepository) Update(ctx context.Context, feed *domain.Feed) error {
+	tx, err := r.db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	historyQuery := `
+		INSERT INTO feed_url_history (feed_id, old_url, new_url, changed_at)
+		SELECT id, url, $2, $3 FROM feeds WHERE id = $1 AND url <> $2
+	`
+	if _, err := tx.ExecContext(ctx, historyQuery, feed.ID, feed.URL, feed.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to record feed url history: %w", err)
+	}
+
 	query := `
 		UPDATE feeds 
-		SET updated_at = $1, last_fetched_at = $2
-		WHERE id = $3
+		SET updated_at = $1, last_fetched_at = $2, etag = $3, last_modified = $4,
+			url = $5, redirect_url = $6, redirect_count = $7,
+			status = CASE WHEN status = 'paused' THEN status ELSE $8 END, not_found_count = $9,
+			consecutive_failures = $10, last_error = $11, last_error_at = $12, next_fetch_at = $13,
+			ttl_seconds = $14, update_period_seconds = $15, skip_hours = $16, skip_days = $17
+		WHERE id = $18
 	`
-	_, err := r.db.conn.ExecContext(ctx, query, feed.UpdatedAt, feed.LastFetchedAt, feed.ID)
+	_, err = tx.ExecContext(ctx, query,
+		feed.UpdatedAt, feed.LastFetchedAt, feed.ETag, feed.LastModified,
+		feed.URL, feed.RedirectURL, feed.RedirectCount,
+		feed.Status, feed.NotFoundCount,
+		feed.ConsecutiveFailures, feed.LastError, feed.LastErrorAt, feed.NextFetchAt,
+		int64(feed.Schedule.TTL.Seconds()), int64(feed.Schedule.UpdatePeriod.Seconds()),
+		pq.Array(hoursToInt64(feed.Schedule.SkipHours)), pq.Array(daysToInt64(feed.Schedule.SkipDays)),
+		feed.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update feed: %w", err)
 	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus sets the status of the named feed on behalf of an operator
+// and clears its failure counts and backoff.
+func (r *FeedRepository) UpdateStatus(ctx context.Context, name string, status domain.FeedStatus) error {
+	query := `
+		UPDATE feeds
+		SET status = $1, not_found_count = 0, consecutive_failures = 0, next_fetch_at = NULL, updated_at = NOW()
+		WHERE name = $2
+	`
+	result, err := r.db.conn.ExecContext(ctx, query, status, name)
+	if err != nil {
+		return fmt.Errorf("failed to update feed status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateIntervalOverride sets or, with zero, clears the operator's polling
+// interval for the named feed. The feed becomes due at once so the new
+// schedule applies from the next tick.
+func (r *FeedRepository) UpdateIntervalOverride(ctx context.Context, name string, interval time.Duration) error {
+	query := `
+		UPDATE feeds
+		SET interval_override_seconds = $1, next_fetch_at = NULL, updated_at = NOW()
+		WHERE name = $2
+	`
+	result, err := r.db.conn.ExecContext(ctx, query, int64(interval.Seconds()), name)
+	if err != nil {
+		return fmt.Errorf("failed to update feed interval: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateRequestOptions stores the feed's credentials, custom headers and
+// proxy. Update leaves them alone, so only an operator's edit changes them.
+func (r *FeedRepository) UpdateRequestOptions(ctx context.Context, feed *domain.Feed) error {
+	headers, err := marshalHeaders(feed.Headers)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE feeds
+		SET auth_type = $1, auth_username = $2, auth_secret = $3, headers = $4, proxy_url = $5,
+			updated_at = NOW()
+		WHERE id = $6
+	`
+	result, err := r.db.conn.ExecContext(ctx, query,
+		string(feed.Auth.Type), feed.Auth.Username, feed.Auth.Secret, headers, feed.ProxyURL, feed.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update feed request options: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
 	return nil
 }
 
+func (r *FeedRepository) ListByStatus(ctx context.Context, status domain.FeedStatus) ([]*domain.Feed, error) {
+	query := `
+		SELECT ` + feedColumns + `
+		FROM feeds WHERE status = $1 ORDER BY updated_at DESC
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feeds: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanFeeds(rows)
+}
+
+// GetMostOutdated returns scheduled feeds whose backoff has expired, least
+// recently fetched first.
 func (r *FeedRepository) GetMostOutdated(ctx context.Context, limit int) ([]*domain.Feed, error) {
 	query := `
-		SELECT id, created_at, updated_at, name, url, last_fetched_at
+		SELECT ` + feedColumns + `
 		FROM feeds 
+		WHERE status = ANY($3)
+		AND (next_fetch_at IS NULL OR next_fetch_at <= $2)
 		ORDER BY COALESCE(last_fetched_at, '1970-01-01'::timestamp) ASC
 		LIMIT $1
 	`
-	rows, err := r.db.conn.QueryContext(ctx, query, limit)
+	var statuses []string
+	for _, status := range domain.ScheduledFeedStatuses() {
+		statuses = append(statuses, string(status))
+	}
+	rows, err := r.db.conn.QueryContext(ctx, query, limit, time.Now(), pq.Array(statuses))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get outdated feeds: %w", err)
 	}
@@ -140,8 +277,7 @@ func (r *FeedRepository) GetMostOutdated(ctx context.Context, limit int) ([]*dom
 func (r *FeedRepository) scanFeeds(rows *sql.Rows) ([]*domain.Feed, error) {
 	var feeds []*domain.Feed
 	for rows.Next() {
-		feed := &domain.Feed{}
-		err := rows.Scan(&feed.ID, &feed.CreatedAt, &feed.UpdatedAt, &feed.Name, &feed.URL, &feed.LastFetchedAt)
+		feed, err := scanFeed(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan feed: %w", err)
 		}
@@ -149,3 +285,78 @@ func (r *FeedRepository) scanFeeds(rows *sql.Rows) ([]*domain.Feed, error) {
 	}
 	return feeds, rows.Err()
 }
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFeed(row rowScanner) (*domain.Feed, error) {
+	feed := &domain.Feed{}
+	var ttlSeconds, updatePeriodSeconds, overrideSeconds int64
+	var skipHours, skipDays []int64
+	var authType string
+	var headers []byte
+	err := row.Scan(
+		&feed.ID, &feed.CreatedAt, &feed.UpdatedAt, &feed.Name, &feed.URL, &feed.LastFetchedAt,
+		&feed.ETag, &feed.LastModified, pq.Array(&feed.StripParams),
+		&feed.RedirectURL, &feed.RedirectCount, &feed.Status, &feed.NotFoundCount,
+		&feed.ConsecutiveFailures, &feed.LastError, &feed.LastErrorAt, &feed.NextFetchAt,
+		&ttlSeconds, &updatePeriodSeconds, pq.Array(&skipHours), pq.Array(&skipDays), &overrideSeconds,
+		&authType, &feed.Auth.Username, &feed.Auth.Secret, &headers, &feed.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	feed.Auth.Type = domain.AuthType(authType)
+	if err := json.Unmarshal(headers, &feed.Headers); err != nil {
+		return nil, fmt.Errorf("failed to decode feed headers: %w", err)
+	}
+
+	feed.Schedule = domain.ScheduleHints{
+		TTL:          time.Duration(ttlSeconds) * time.Second,
+		UpdatePeriod: time.Duration(updatePeriodSeconds) * time.Second,
+	}
+	for _, hour := range skipHours {
+		feed.Schedule.SkipHours = append(feed.Schedule.SkipHours, int(hour))
+	}
+	for _, day := range skipDays {
+		feed.Schedule.SkipDays = append(feed.Schedule.SkipDays, time.Weekday(day))
+	}
+	feed.IntervalOverride = time.Duration(overrideSeconds) * time.Second
+	return feed, nil
+}
+
+func hoursToInt64(hours []int) []int64 {
+	values := make([]int64, 0, len(hours))
+	for _, hour := range hours {
+		values = append(values, int64(hour))
+	}
+	return values
+}
+
+func daysToInt64(days []time.Weekday) []int64 {
+	values := make([]int64, 0, len(days))
+	for _, day := range days {
+		values = append(values, int64(day))
+	}
+	return values
+}
+
+func marshalHeaders(headers map[string]string) ([]byte, error) {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed headers: %w", err)
+	}
+	return data, nil
+}
+
+// nonNilStrings keeps pq from sending NULL for an empty slice into a
+// NOT NULL array column.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rsshub/internal/domain"
+)
+
+type FetchRunRepository struct {
+	db *DB
+}
+
+func NewFetchRunRepository(db *DB) *FetchRunRepository {
+	return &FetchRunRepository{db: db}
+}
+
+func (r *FetchRunRepository) Create(ctx context.Context, run *domain.FetchRun) error {
+	query := `
+		INSERT INTO fetch_runs (id, feed_id, started_at, duration_ms, http_status, bytes,
+			items_seen, new_articles, truncated, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.conn.ExecContext(ctx, query,
+		run.ID, run.FeedID, run.StartedAt, run.Duration.Milliseconds(), run.StatusCode, run.BytesRead,
+		run.ItemsSeen, run.NewArticles, run.Truncated, run.Error)
+	if err != nil {
+		return fmt.Errorf("failed to create fetch run: %w", err)
+	}
+	return nil
+}
+
+// List returns the most recent runs matching filter, newest first.
+func (r *FetchRunRepository) List(ctx context.Context, filter domain.FetchRunFilter) ([]*domain.FetchRun, error) {
+	query := `
+		SELECT r.id, r.feed_id, f.name, r.started_at, r.duration_ms, r.http_status, r.bytes,
+			r.items_seen, r.new_articles, r.truncated, r.error
+		FROM fetch_runs r
+		INNER JOIN feeds f ON r.feed_id = f.id
+		WHERE ($1 = '' OR f.name = $1)
+		AND (NOT $2 OR r.error <> '')
+		ORDER BY r.started_at DESC
+		LIMIT $3
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query, filter.FeedName, filter.FailedOnly, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fetch runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.FetchRun
+	for rows.Next() {
+		run := &domain.FetchRun{}
+		var durationMs int64
+		err := rows.Scan(&run.ID, &run.FeedID, &run.FeedName, &run.StartedAt, &durationMs,
+			&run.StatusCode, &run.BytesRead, &run.ItemsSeen, &run.NewArticles, &run.Truncated, &run.Error)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fetch run: %w", err)
+		}
+		run.Duration = time.Duration(durationMs) * time.Millisecond
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+func (r *FetchRunRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM fetch_runs WHERE started_at < $1`
+	result, err := r.db.conn.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old fetch runs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
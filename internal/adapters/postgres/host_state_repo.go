@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"rsshub/internal/domain"
+)
+
+type HostStateRepository struct {
+	db *DB
+}
+
+func NewHostStateRepository(db *DB) *HostStateRepository {
+	return &HostStateRepository{db: db}
+}
+
+func (r *HostStateRepository) Replace(ctx context.Context, states []domain.HostState) error {
+	tx, err := r.db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM host_states`); err != nil {
+		return fmt.Errorf("failed to clear host states: %w", err)
+	}
+
+	query := `
+		INSERT INTO host_states (host, in_flight, requests, paused_until, pause_reason,
+			breaker, breaker_failures, breaker_open_until, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, state := range states {
+		breaker := state.Breaker
+		if breaker == "" {
+			breaker = domain.BreakerClosed
+		}
+		_, err := stmt.ExecContext(ctx, state.Host, state.InFlight, state.Requests,
+			state.PausedUntil, state.PauseReason, string(breaker), state.BreakerFailures,
+			state.BreakerOpenUntil, state.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save host state for %s: %w", state.Host, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *HostStateRepository) List(ctx context.Context) ([]domain.HostState, error) {
+	query := `
+		SELECT host, in_flight, requests, paused_until, pause_reason,
+			breaker, breaker_failures, breaker_open_until, updated_at
+		FROM host_states ORDER BY host
+	`
+	rows, err := r.db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list host states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []domain.HostState
+	for rows.Next() {
+		var state domain.HostState
+		var breaker string
+		err := rows.Scan(&state.Host, &state.InFlight, &state.Requests,
+			&state.PausedUntil, &state.PauseReason, &breaker, &state.BreakerFailures,
+			&state.BreakerOpenUntil, &state.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan host state: %w", err)
+		}
+		state.Breaker = domain.BreakerState(breaker)
+		states = append(states, state)
+	}
+
+	return states, rows.Err()
+}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix marks values written by AESGCMCipher. Values without it
+// were stored before encryption was enabled and are passed through as is
+// until rotate-key encrypts them.
+const encryptedPrefix = "enc:v1:"
+
+var ErrNoSecretKey = errors.New("no secret key configured, set CLI_APP_SECRET_KEY or CLI_APP_SECRET_KEY_FILE")
+
+// AESGCMCipher encrypts secrets with AES-GCM under the current key and
+// decrypts them with whichever configured key they were written with.
+// Each value records the ID of its key, so keys can be rotated by adding
+// the new one, re-encrypting every row and then dropping the old one.
+type AESGCMCipher struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewAESGCMCipher takes base64 encoded 16, 24 or 32 byte keys. key is used
+// for encryption; previous keys are only used to decrypt. An empty key
+// leaves the cipher able to read unencrypted values only.
+func NewAESGCMCipher(key string, previous ...string) (*AESGCMCipher, error) {
+	c := &AESGCMCipher{keys: make(map[string]cipher.AEAD)}
+	for i, encoded := range append([]string{key}, previous...) {
+		if encoded == "" {
+			continue
+		}
+		id, aead, err := parseKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			c.currentID = id
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+func parseKey(encoded string) (string, cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", nil, fmt.Errorf("secret key is not valid base64: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4]), aead, nil
+}
+
+// additionalData binds a ciphertext to its key and to where it is stored.
+func additionalData(keyID, scope string) []byte {
+	return []byte(keyID + "\x00" + scope)
+}
+
+// Encrypt seals plaintext so that it only decrypts with the same scope.
+func (c *AESGCMCipher) Encrypt(plaintext, scope string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	if c.currentID == "" {
+		return "", ErrNoSecretKey
+	}
+
+	aead := c.keys[c.currentID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), additionalData(c.currentID, scope))
+	return encryptedPrefix + c.currentID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *AESGCMCipher) Decrypt(ciphertext, scope string) (string, error) {
+	rest, ok := strings.CutPrefix(ciphertext, encryptedPrefix)
+	if !ok {
+		return ciphertext, nil
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted secret")
+	}
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", fmt.Errorf("secret was encrypted with unknown key %s", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted secret")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData(id, scope))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret with key %s: %w", id, err)
+	}
+	return string(plaintext), nil
+}
+
+func (c *AESGCMCipher) Reencrypt(ciphertext, scope string) (string, error) {
+	plaintext, err := c.Decrypt(ciphertext, scope)
+	if err != nil {
+		return "", err
+	}
+	return c.Encrypt(plaintext, scope)
+}
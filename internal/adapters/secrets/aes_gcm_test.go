@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	oldKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	newKey = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+)
+
+const (
+	authScope  = "8f14e45f-ceea-467f-a8f4-4d6e3e5e7a1b/auth_secret"
+	proxyScope = "8f14e45f-ceea-467f-a8f4-4d6e3e5e7a1b/proxy_url"
+	otherScope = "c9f0f895-fb98-4b9e-9c3f-5b1f6b8c2d4e/auth_secret"
+)
+
+func mustCipher(t *testing.T, key string, previous ...string) *AESGCMCipher {
+	t.Helper()
+	c, err := NewAESGCMCipher(key, previous...)
+	if err != nil {
+		t.Fatalf("NewAESGCMCipher: %v", err)
+	}
+	return c
+}
+
+func TestDecrypt(t *testing.T) {
+	tests := []struct {
+		name         string
+		encryptKey   string
+		decryptKeys  []string
+		decryptScope string
+		wantErr      bool
+	}{
+		{"round trip", oldKey, []string{oldKey}, authScope, false},
+		{"other column", oldKey, []string{oldKey}, proxyScope, true},
+		{"other feed", oldKey, []string{oldKey}, otherScope, true},
+		{"unknown key", oldKey, []string{newKey}, authScope, true},
+		{"no key", oldKey, []string{""}, authScope, true},
+		{"previous key after rotation", oldKey, []string{newKey, oldKey}, authScope, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := mustCipher(t, tt.encryptKey).Encrypt("s3cret", authScope)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if !strings.HasPrefix(ciphertext, encryptedPrefix) || strings.Contains(ciphertext, "s3cret") {
+				t.Fatalf("Encrypt = %q, want an encrypted value", ciphertext)
+			}
+
+			got, err := mustCipher(t, tt.decryptKeys[0], tt.decryptKeys[1:]...).Decrypt(ciphertext, tt.decryptScope)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Decrypt = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if got != "s3cret" {
+				t.Fatalf("Decrypt = %q, want %q", got, "s3cret")
+			}
+		})
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	got, err := mustCipher(t, oldKey).Decrypt("stored before encryption", authScope)
+	if err != nil || got != "stored before encryption" {
+		t.Fatalf("Decrypt = %q, %v; want the value unchanged", got, err)
+	}
+}
+
+func TestEncryptWithoutKey(t *testing.T) {
+	if _, err := mustCipher(t, "").Encrypt("s3cret", authScope); !errors.Is(err, ErrNoSecretKey) {
+		t.Fatalf("Encrypt error = %v, want ErrNoSecretKey", err)
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	encrypted, err := mustCipher(t, oldKey).Encrypt("s3cret", authScope)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{"under the previous key", encrypted},
+		{"plaintext", "s3cret"},
+	}
+
+	rotating := mustCipher(t, newKey, oldKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reencrypted, err := rotating.Reencrypt(tt.stored, authScope)
+			if err != nil {
+				t.Fatalf("Reencrypt: %v", err)
+			}
+
+			got, err := mustCipher(t, newKey).Decrypt(reencrypted, authScope)
+			if err != nil {
+				t.Fatalf("Decrypt with the new key alone: %v", err)
+			}
+			if got != "s3cret" {
+				t.Fatalf("Decrypt = %q, want %q", got, "s3cret")
+			}
+			if _, err := mustCipher(t, oldKey).Decrypt(reencrypted, authScope); err == nil {
+				t.Fatal("Decrypt with the old key succeeded, want an error")
+			}
+			if _, err := rotating.Reencrypt(reencrypted, proxyScope); err == nil {
+				t.Fatal("Reencrypt with another scope succeeded, want an error")
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"rsshub/internal/domain"
 )
 
 type EnvConfig struct{}
@@ -55,6 +58,182 @@ func (c *EnvConfig) GetDefaultWorkersCount() int {
 	return workers
 }
 
+// GetStrippedParams returns the query parameters removed from article and
+// feed URLs, from a comma-separated CLI_APP_STRIP_PARAMS. Without it the
+// built-in tracking parameter list is used.
+func (c *EnvConfig) GetStrippedParams() []string {
+	value := getEnv("CLI_APP_STRIP_PARAMS", "")
+	if value == "" {
+		return domain.DefaultTrackingParams
+	}
+	return strings.Split(value, ",")
+}
+
+// GetMaxFeedBytes caps how much of a feed response is read, from
+// CLI_APP_MAX_FEED_BYTES. Defaults to 10 MiB.
+func (c *EnvConfig) GetMaxFeedBytes() int64 {
+	bytesStr := getEnv("CLI_APP_MAX_FEED_BYTES", "10485760")
+	maxBytes, err := strconv.ParseInt(bytesStr, 10, 64)
+	if err != nil || maxBytes <= 0 {
+		return 10 << 20
+	}
+	return maxBytes
+}
+
+// GetMaxFeedItems caps how many items are taken from a single fetch, from
+// CLI_APP_MAX_FEED_ITEMS. Zero disables the cap.
+func (c *EnvConfig) GetMaxFeedItems() int {
+	itemsStr := getEnv("CLI_APP_MAX_FEED_ITEMS", "500")
+	items, err := strconv.Atoi(itemsStr)
+	if err != nil || items < 0 {
+		return 500
+	}
+	return items
+}
+
+// GetRedirectThreshold is how many consecutive permanent redirects move a
+// feed to its new URL, from CLI_APP_REDIRECT_THRESHOLD. Zero disables it.
+func (c *EnvConfig) GetRedirectThreshold() int {
+	thresholdStr := getEnv("CLI_APP_REDIRECT_THRESHOLD", strconv.Itoa(domain.DefaultRedirectThreshold))
+	threshold, err := strconv.Atoi(thresholdStr)
+	if err != nil || threshold < 0 {
+		return domain.DefaultRedirectThreshold
+	}
+	return threshold
+}
+
+// GetGoneThreshold is how many consecutive 404 or unknown host failures
+// mark a feed gone, from CLI_APP_GONE_THRESHOLD. Zero disables it.
+func (c *EnvConfig) GetGoneThreshold() int {
+	thresholdStr := getEnv("CLI_APP_GONE_THRESHOLD", strconv.Itoa(domain.DefaultGoneThreshold))
+	threshold, err := strconv.Atoi(thresholdStr)
+	if err != nil || threshold < 0 {
+		return domain.DefaultGoneThreshold
+	}
+	return threshold
+}
+
+// GetBackoffBase is the retry delay after a feed's first failed fetch, from
+// CLI_APP_BACKOFF_BASE. It doubles with each further failure.
+func (c *EnvConfig) GetBackoffBase() time.Duration {
+	return getDuration("CLI_APP_BACKOFF_BASE", domain.DefaultBackoffBase)
+}
+
+// GetBackoffMax caps the retry delay of failing feeds, from
+// CLI_APP_BACKOFF_MAX.
+func (c *EnvConfig) GetBackoffMax() time.Duration {
+	return getDuration("CLI_APP_BACKOFF_MAX", domain.DefaultBackoffMax)
+}
+
+// GetFetchHistoryRetention is how long fetch runs are kept, from
+// CLI_APP_FETCH_HISTORY_RETENTION. Zero keeps them forever.
+func (c *EnvConfig) GetFetchHistoryRetention() time.Duration {
+	return getDuration("CLI_APP_FETCH_HISTORY_RETENTION", domain.DefaultFetchHistoryRetention)
+}
+
+// GetHostMaxConcurrent is how many requests all workers together may have
+// open to one host, from CLI_APP_HOST_MAX_CONCURRENT. Defaults to 2.
+func (c *EnvConfig) GetHostMaxConcurrent() int {
+	concurrentStr := getEnv("CLI_APP_HOST_MAX_CONCURRENT", "2")
+	concurrent, err := strconv.Atoi(concurrentStr)
+	if err != nil || concurrent <= 0 {
+		return 2
+	}
+	return concurrent
+}
+
+// GetHostMinDelay is the minimum time between the starts of two requests
+// to one host, from CLI_APP_HOST_MIN_DELAY. Defaults to 1s.
+func (c *EnvConfig) GetHostMinDelay() time.Duration {
+	return getDuration("CLI_APP_HOST_MIN_DELAY", time.Second)
+}
+
+// GetBreakerThreshold is how many consecutive failures open a host's
+// circuit breaker, from CLI_APP_BREAKER_THRESHOLD. Defaults to 5.
+func (c *EnvConfig) GetBreakerThreshold() int {
+	thresholdStr := getEnv("CLI_APP_BREAKER_THRESHOLD", "5")
+	threshold, err := strconv.Atoi(thresholdStr)
+	if err != nil || threshold <= 0 {
+		return 5
+	}
+	return threshold
+}
+
+// GetBreakerCooldown is how long an open circuit breaker skips a host
+// before probing it again, from CLI_APP_BREAKER_COOLDOWN. Defaults to 5m.
+func (c *EnvConfig) GetBreakerCooldown() time.Duration {
+	return getDuration("CLI_APP_BREAKER_COOLDOWN", 5*time.Minute)
+}
+
+// GetProxyURL is the outbound HTTP, HTTPS or SOCKS5 proxy for feed
+// requests, from CLI_APP_PROXY_URL. Without it HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY are honoured.
+func (c *EnvConfig) GetProxyURL() string {
+	return getEnv("CLI_APP_PROXY_URL", "")
+}
+
+// GetCABundlePath names a PEM file of extra trusted CAs, from
+// CLI_APP_CA_BUNDLE.
+func (c *EnvConfig) GetCABundlePath() string {
+	return getEnv("CLI_APP_CA_BUNDLE", "")
+}
+
+// GetClientCertPath and GetClientKeyPath name the PEM client certificate
+// and key presented to feeds that require mTLS, from CLI_APP_CLIENT_CERT
+// and CLI_APP_CLIENT_KEY.
+func (c *EnvConfig) GetClientCertPath() string {
+	return getEnv("CLI_APP_CLIENT_CERT", "")
+}
+
+func (c *EnvConfig) GetClientKeyPath() string {
+	return getEnv("CLI_APP_CLIENT_KEY", "")
+}
+
+// GetMinTLSVersion is the lowest TLS version accepted from feeds, from
+// CLI_APP_MIN_TLS_VERSION. Defaults to 1.2.
+func (c *EnvConfig) GetMinTLSVersion() string {
+	return getEnv("CLI_APP_MIN_TLS_VERSION", "1.2")
+}
+
+// GetSecretKey returns the base64 key that encrypts stored feed
+// credentials, from CLI_APP_SECRET_KEY or the file named by
+// CLI_APP_SECRET_KEY_FILE. It is empty when neither is set.
+func (c *EnvConfig) GetSecretKey() (string, error) {
+	return getSecret("CLI_APP_SECRET_KEY")
+}
+
+// GetPreviousSecretKey returns the key being rotated away from, from
+// CLI_APP_PREVIOUS_SECRET_KEY or CLI_APP_PREVIOUS_SECRET_KEY_FILE. It is
+// only needed until rotate-key has re-encrypted every credential.
+func (c *EnvConfig) GetPreviousSecretKey() (string, error) {
+	return getSecret("CLI_APP_PREVIOUS_SECRET_KEY")
+}
+
+// getSecret reads key from the environment, or else from the file named by
+// key_FILE.
+func getSecret(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	duration, err := time.ParseDuration(getEnv(key, defaultValue.String()))
+	if err != nil || duration < 0 {
+		return defaultValue
+	}
+	return duration
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"rsshub/internal/ports"
 )
 
 func (s *AggregatorService) keepAliveLoop() {
@@ -20,10 +22,23 @@ func (s *AggregatorService) keepAliveLoop() {
 			if err := s.ipcLock.KeepAlive(context.Background()); err != nil {
 				log.Printf("Warning: failed to keep lock alive: %v\n", err)
 			}
+			s.publishHostStates()
 		}
 	}
 }
 
+// publishHostStates stores the fetcher's per-host state, if it keeps any,
+// where the status command can read it.
+func (s *AggregatorService) publishHostStates() {
+	reporter, ok := s.rssFetcher.(ports.HostStateReporter)
+	if !ok {
+		return
+	}
+	if err := s.hostRepo.Replace(context.Background(), reporter.HostStates()); err != nil {
+		log.Printf("Warning: failed to publish host states: %v\n", err)
+	}
+}
+
 func (s *AggregatorService) commandListener() {
 	defer s.wg.Done()
 	ticker := time.NewTicker(2 * time.Second)
@@ -12,10 +12,18 @@ import (
 )
 
 type AggregatorService struct {
-	feedRepo    ports.FeedRepository
-	articleRepo ports.ArticleRepository
-	rssFetcher  ports.RSSFetcher
-	ipcLock     ports.IPCLock
+	feedRepo     ports.FeedRepository
+	articleRepo  ports.ArticleRepository
+	fetchRunRepo ports.FetchRunRepository
+	hostRepo     ports.HostStateRepository
+	rssFetcher   ports.RSSFetcher
+	ipcLock      ports.IPCLock
+
+	canonicalizer *domain.URLCanonicalizer
+	policy        domain.FeedPolicy
+
+	historyRetention time.Duration
+	lastHistoryPrune time.Time
 
 	mu             sync.RWMutex
 	interval       time.Duration
@@ -33,19 +41,29 @@ type AggregatorService struct {
 func NewAggregatorService(
 	feedRepo ports.FeedRepository,
 	articleRepo ports.ArticleRepository,
+	fetchRunRepo ports.FetchRunRepository,
+	hostRepo ports.HostStateRepository,
 	rssFetcher ports.RSSFetcher,
 	ipcLock ports.IPCLock,
+	canonicalizer *domain.URLCanonicalizer,
+	policy domain.FeedPolicy,
+	historyRetention time.Duration,
 	defaultInterval time.Duration,
 	defaultWorkers int,
 ) ports.AggregatorPort {
 	return &AggregatorService{
-		feedRepo:     feedRepo,
-		articleRepo:  articleRepo,
-		rssFetcher:   rssFetcher,
-		ipcLock:      ipcLock,
-		interval:     defaultInterval,
-		workersCount: defaultWorkers,
-		jobs:         make(chan *domain.Feed, 100),
+		feedRepo:         feedRepo,
+		articleRepo:      articleRepo,
+		fetchRunRepo:     fetchRunRepo,
+		hostRepo:         hostRepo,
+		rssFetcher:       rssFetcher,
+		ipcLock:          ipcLock,
+		canonicalizer:    canonicalizer,
+		policy:           policy,
+		historyRetention: historyRetention,
+		interval:         defaultInterval,
+		workersCount:     defaultWorkers,
+		jobs:             make(chan *domain.Feed, 100),
 	}
 }
 
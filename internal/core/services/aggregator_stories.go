@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rsshub/internal/domain"
+)
+
+// assignStories clusters new articles with near-duplicates saved within
+// domain.StoryWindow, including ones earlier in the same batch.
+func (s *AggregatorService) assignStories(ctx context.Context, articles []*domain.Article) error {
+	candidates, err := s.articleRepo.GetRecentFingerprints(ctx, time.Now().Add(-domain.StoryWindow))
+	if err != nil {
+		return fmt.Errorf("failed to load story candidates: %w", err)
+	}
+
+	for _, article := range articles {
+		article.JoinStory(candidates)
+		candidates = append(candidates, article.StoryFingerprint())
+	}
+
+	return nil
+}
@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"rsshub/internal/domain"
+
+	"github.com/google/uuid"
 )
 
 func (s *AggregatorService) startWorkers(count int) {
@@ -45,6 +49,8 @@ func (s *AggregatorService) processBatch() {
 
 	log.Printf("DEBUG: Found %d outdated feeds to process", len(feeds))
 
+	s.pruneFetchHistory()
+
 	for _, feed := range feeds {
 		select {
 		case <-s.ctx.Done():
@@ -75,15 +81,95 @@ func (s *AggregatorService) worker(ctx context.Context, id int) {
 func (s *AggregatorService) processFeed(feed *domain.Feed) {
 	log.Printf("Worker processing feed: %s (%s)\n", feed.Name, feed.URL)
 
-	rssFeed, err := s.rssFetcher.Fetch(context.Background(), feed.URL)
+	run := domain.NewFetchRun(feed)
+	run.Finish(s.fetchFeed(feed, run))
+	if err := s.fetchRunRepo.Create(context.Background(), run); err != nil {
+		log.Printf("Error recording fetch run for feed %s: %v\n", feed.Name, err)
+	}
+}
+
+// fetchFeed fetches one feed and stores its new articles, filling in run as
+// it goes. The returned error is the one that ended the run early.
+func (s *AggregatorService) fetchFeed(feed *domain.Feed, run *domain.FetchRun) error {
+	s.canonicalizeDedupKeys(feed)
+
+	req := feed.FetchRequest()
+	since, err := s.articleRepo.GetLatestPublishedAt(context.Background(), feed.ID)
+	if err != nil {
+		log.Printf("Error loading latest article date for feed %s: %v\n", feed.Name, err)
+	}
+	req.Since = since
+
+	result, err := s.rssFetcher.Fetch(context.Background(), req)
+	if until, skipped := hostSkippedUntil(err); skipped {
+		// The host is throttled or down; that says nothing about the feed.
+		log.Printf("Skipping feed %s: %v\n", feed.Name, err)
+		feed.NextFetchAt = &until
+		if err := s.feedRepo.Update(context.Background(), feed); err != nil {
+			log.Printf("Error updating feed %s: %v\n", feed.Name, err)
+		}
+		return err
+	}
 	if err != nil {
+		var statusErr *domain.StatusError
+		if errors.As(err, &statusErr) {
+			run.StatusCode = statusErr.StatusCode
+		}
+
 		log.Printf("Error fetching RSS feed %s: %v\n", feed.Name, err)
-		return
+		if feed.RecordFetchFailure(err, s.policy) {
+			log.Printf("Feed %s is gone and will no longer be fetched\n", feed.Name)
+		}
+		log.Printf("Feed %s failed %d times in a row, next attempt after %s\n",
+			feed.Name, feed.ConsecutiveFailures, feed.NextFetchAt.Format(time.RFC3339))
+		if err := s.feedRepo.Update(context.Background(), feed); err != nil {
+			log.Printf("Error updating feed %s: %v\n", feed.Name, err)
+		}
+		return err
+	}
+
+	run.StatusCode = result.StatusCode
+	run.BytesRead = result.BytesRead
+	feed.RecordFetchSuccess()
+
+	feed.SetCacheValidators(result.ETag, result.LastModified)
+	s.followRedirects(feed, result)
+
+	if result.NotModified {
+		log.Printf("Feed %s not modified since last fetch\n", feed.Name)
+		feed.ScheduleNextFetch(time.Now(), s.GetInterval())
+		feed.MarkAsFetched()
+		if err := s.feedRepo.Update(context.Background(), feed); err != nil {
+			log.Printf("Error updating feed %s: %v\n", feed.Name, err)
+		}
+		return nil
 	}
 
+	run.ItemsSeen = len(result.Feed.Items)
+	run.Truncated = result.Feed.Truncated
+	feed.Schedule = result.Feed.Schedule
+	feed.ScheduleNextFetch(time.Now(), s.GetInterval())
+	for _, warning := range result.Feed.Warnings {
+		log.Printf("Warning: feed %s: %s\n", feed.Name, warning)
+	}
+	if result.Feed.Truncated {
+		log.Printf("Feed %s truncated after %d items (%d bytes read)\n",
+			feed.Name, len(result.Feed.Items), result.BytesRead)
+	}
+
+	canonicalizer := s.canonicalizer.WithParams(feed.StripParams)
+
 	var newArticles []*domain.Article
-	for _, item := range rssFeed.Channel.Items {
-		exists, err := s.articleRepo.Exists(context.Background(), item.Link, feed.ID)
+	seen := make(map[string]bool)
+	for _, item := range result.Feed.Items {
+		article := domain.NewArticleFromItem(&item, feed.ID, canonicalizer)
+		if seen[article.DedupKey] {
+			continue
+		}
+		seen[article.DedupKey] = true
+
+		linkKey := item.LinkDedupKey(canonicalizer)
+		exists, err := s.articleRepo.Exists(context.Background(), article.DedupKey, linkKey, feed.ID)
 		if err != nil {
 			log.Printf("Error checking article existence: %v\n", err)
 			continue
@@ -92,16 +178,21 @@ func (s *AggregatorService) processFeed(feed *domain.Feed) {
 			continue
 		}
 
-		pubDate := item.ParsePubDate()
-		article := domain.NewArticle(item.Title, item.Link, item.Description, pubDate, feed.ID)
 		newArticles = append(newArticles, article)
 	}
 
 	if len(newArticles) > 0 {
+		// Clustering is best effort: without it every article simply
+		// remains a story of its own.
+		if err := s.assignStories(context.Background(), newArticles); err != nil {
+			log.Printf("Error clustering articles for feed %s: %v\n", feed.Name, err)
+		}
+
 		if err := s.articleRepo.CreateBatch(context.Background(), newArticles); err != nil {
 			log.Printf("Error saving articles for feed %s: %v\n", feed.Name, err)
-			return
+			return err
 		}
+		run.NewArticles = len(newArticles)
 		log.Printf("Saved %d new articles for feed %s\n", len(newArticles), feed.Name)
 	}
 
@@ -109,4 +200,110 @@ func (s *AggregatorService) processFeed(feed *domain.Feed) {
 	if err := s.feedRepo.Update(context.Background(), feed); err != nil {
 		log.Printf("Error updating feed %s: %v\n", feed.Name, err)
 	}
+	return nil
+}
+
+// hostSkippedUntil reports whether the fetch was not attempted because its
+// host is paused or its circuit is open, and until when.
+func hostSkippedUntil(err error) (time.Time, bool) {
+	var throttledErr *domain.HostThrottledError
+	if errors.As(err, &throttledErr) {
+		return throttledErr.Until, true
+	}
+	var circuitErr *domain.CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return circuitErr.Until, true
+	}
+	return time.Time{}, false
+}
+
+// followRedirects moves the feed to the location it has been permanently
+// redirected to once the policy threshold is reached. The repository keeps
+// the old URL when the feed is next updated.
+func (s *AggregatorService) followRedirects(feed *domain.Feed, result *domain.FetchResult) {
+	location := result.PermanentRedirect()
+	if location != "" {
+		location = s.canonicalizer.WithParams(feed.StripParams).Canonicalize(location)
+	}
+
+	oldURL := feed.URL
+	moved, err := feed.RecordRedirect(location, s.policy.RedirectThreshold)
+	if err != nil {
+		log.Printf("Not moving feed %s: %v\n", feed.Name, err)
+	}
+	if moved {
+		log.Printf("Feed %s moved permanently from %s to %s\n", feed.Name, oldURL, feed.URL)
+	}
+}
+
+// canonicalizeDedupKeys rewrites the feed's link keys stored before
+// canonicalization with its canonicalizer, so that the fetch recognises the
+// articles it already has. Once a feed is done this is a single lookup of
+// an empty index, so the work after an upgrade is spread over the feeds'
+// next fetches.
+func (s *AggregatorService) canonicalizeDedupKeys(feed *domain.Feed) {
+	const batchSize = 500
+
+	canonicalizer := s.canonicalizer.WithParams(feed.StripParams)
+	updated := 0
+	for {
+		articles, err := s.articleRepo.ListNonCanonicalKeys(context.Background(), feed.ID, batchSize)
+		if err != nil {
+			log.Printf("Error loading dedup keys of feed %s: %v\n", feed.Name, err)
+			return
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		keys := make(map[uuid.UUID]string, len(articles))
+		owners := make(map[string]int, len(articles))
+		for _, article := range articles {
+			item := domain.FeedItem{Link: article.Link}
+			key := item.LinkDedupKey(canonicalizer)
+			if key == "" {
+				key = article.DedupKey
+			}
+			keys[article.ID] = key
+			owners[key]++
+		}
+		// Articles that canonicalize to the same key are duplicates from
+		// before canonicalization; they all keep their old keys.
+		for _, article := range articles {
+			if owners[keys[article.ID]] > 1 {
+				keys[article.ID] = article.DedupKey
+			}
+		}
+
+		if err := s.articleRepo.UpdateDedupKeys(context.Background(), keys); err != nil {
+			log.Printf("Error canonicalizing dedup keys of feed %s: %v\n", feed.Name, err)
+			return
+		}
+		updated += len(articles)
+		if len(articles) < batchSize {
+			break
+		}
+	}
+
+	if updated > 0 {
+		log.Printf("Canonicalized %d dedup keys of feed %s\n", updated, feed.Name)
+	}
+}
+
+// pruneFetchHistory deletes fetch runs older than the retention period, at
+// most once an hour.
+func (s *AggregatorService) pruneFetchHistory() {
+	if s.historyRetention <= 0 || time.Since(s.lastHistoryPrune) < time.Hour {
+		return
+	}
+	s.lastHistoryPrune = time.Now()
+
+	deleted, err := s.fetchRunRepo.DeleteOlderThan(context.Background(), time.Now().Add(-s.historyRetention))
+	if err != nil {
+		log.Printf("Error pruning fetch history: %v\n", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pruned %d fetch runs older than %v\n", deleted, s.historyRetention)
+	}
 }
@@ -27,3 +27,20 @@ func (s *ArticleService) GetArticlesByFeed(ctx context.Context, feedName string,
 	}
 	return s.articleRepo.GetByFeedName(ctx, feedName, limit)
 }
+
+func (s *ArticleService) GetArticlesByCategory(ctx context.Context, feedName, category string, limit int) ([]*domain.Article, error) {
+	if category == "" {
+		return nil, fmt.Errorf("category cannot be empty")
+	}
+	if limit <= 0 {
+		limit = 3
+	}
+	return s.articleRepo.GetByCategory(ctx, feedName, category, limit)
+}
+
+func (s *ArticleService) GetStories(ctx context.Context, limit int) ([]*domain.Story, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.articleRepo.GetStories(ctx, limit)
+}
@@ -2,32 +2,299 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"rsshub/internal/domain"
 	"rsshub/internal/ports"
+
+	"github.com/google/uuid"
 )
 
 type FeedService struct {
-	feedRepo ports.FeedRepository
+	feedRepo      ports.FeedRepository
+	discoverer    ports.FeedDiscoverer
+	rssFetcher    ports.RSSFetcher
+	secrets       ports.SecretCipher
+	canonicalizer *domain.URLCanonicalizer
+}
+
+type AddFeedOptions struct {
+	SkipValidate bool
+	StripParams  []string
+	Auth         domain.FeedAuth
+	Headers      map[string]string
+	ProxyURL     string
 }
 
-func NewFeedService(feedRepo ports.FeedRepository) *FeedService {
+// EditFeedOptions lists the changes to a feed's request options. A nil
+// Auth or ProxyURL keeps the current value; a zero one removes it.
+type EditFeedOptions struct {
+	Auth          *domain.FeedAuth
+	SetHeaders    map[string]string
+	RemoveHeaders []string
+	ProxyURL      *string
+}
+
+func NewFeedService(
+	feedRepo ports.FeedRepository,
+	discoverer ports.FeedDiscoverer,
+	rssFetcher ports.RSSFetcher,
+	secrets ports.SecretCipher,
+	canonicalizer *domain.URLCanonicalizer,
+) *FeedService {
 	return &FeedService{
-		feedRepo: feedRepo,
+		feedRepo:      feedRepo,
+		discoverer:    discoverer,
+		rssFetcher:    rssFetcher,
+		secrets:       secrets,
+		canonicalizer: canonicalizer,
 	}
 }
 
-func (s *FeedService) AddFeed(ctx context.Context, name, url string) error {
+// AddFeed resolves url to a feed, dry-run fetches it and stores it. The
+// returned preview is the parsed content of that dry run; it is nil when
+// opts.SkipValidate is set, in which case url is stored without discovery.
+func (s *FeedService) AddFeed(ctx context.Context, name, url string, opts AddFeedOptions) (*domain.Feed, *domain.ParsedFeed, error) {
 	if name == "" {
-		return fmt.Errorf("feed name cannot be empty")
+		return nil, nil, fmt.Errorf("feed name cannot be empty")
 	}
-	if url == "" {
-		return fmt.Errorf("feed url cannot be empty")
+	if strings.TrimSpace(url) == "" {
+		return nil, nil, fmt.Errorf("feed url cannot be empty")
 	}
 
+	canonicalizer := s.canonicalizer.WithParams(opts.StripParams)
+	url = canonicalizer.Canonicalize(url)
+
 	feed := domain.NewFeed(name, url)
-	return s.feedRepo.Create(ctx, feed)
+	feed.StripParams = opts.StripParams
+
+	// From here on the secrets only exist encrypted; the fetcher decrypts
+	// them for discovery and validation as it does for regular fetches.
+	if err := s.setRequestOptions(feed, opts.Auth, opts.Headers, opts.ProxyURL); err != nil {
+		return nil, nil, err
+	}
+
+	if opts.SkipValidate {
+		if err := s.feedRepo.Create(ctx, feed); err != nil {
+			return nil, nil, err
+		}
+		return feed, nil, nil
+	}
+
+	candidates, err := s.discover(ctx, feed.FetchRequest())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) > 1 {
+		return nil, nil, &domain.MultipleFeedsError{Candidates: candidates}
+	}
+	// The candidate may come from a link in the page, which can point
+	// anywhere; credentials are only meant for the host the operator named.
+	if domain.HasCredentials(opts.Auth, opts.Headers) && domain.HostOf(candidates[0]) != domain.HostOf(url) {
+		return nil, nil, fmt.Errorf("feed found at %s is on another host than %s; "+
+			"to send credentials there, add the feed by that URL", candidates[0], url)
+	}
+
+	feed.URL = canonicalizer.Canonicalize(candidates[0])
+
+	preview, err := s.ValidateFeed(ctx, feed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.feedRepo.Create(ctx, feed); err != nil {
+		return nil, nil, err
+	}
+	return feed, preview, nil
+}
+
+// setRequestOptions encrypts the secrets among auth, headers and proxyURL
+// and stores them on a new feed.
+func (s *FeedService) setRequestOptions(feed *domain.Feed, auth domain.FeedAuth, headers map[string]string, proxyURL string) error {
+	var err error
+	if feed.Auth, err = s.encryptAuth(feed.ID, auth); err != nil {
+		return err
+	}
+	feed.Headers = make(map[string]string, len(headers))
+	for name, value := range headers {
+		if feed.Headers[name], err = s.encryptHeader(feed.ID, name, value); err != nil {
+			return err
+		}
+	}
+	feed.ProxyURL, err = s.encryptProxy(feed.ID, proxyURL)
+	return err
+}
+
+// EditFeed changes the credentials, custom headers and proxy of a feed.
+func (s *FeedService) EditFeed(ctx context.Context, name string, opts EditFeedOptions) (*domain.Feed, error) {
+	if name == "" {
+		return nil, fmt.Errorf("feed name cannot be empty")
+	}
+
+	feed, err := s.feedRepo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("feed not found: %s", name)
+		}
+		return nil, err
+	}
+
+	if opts.Auth != nil {
+		if feed.Auth, err = s.encryptAuth(feed.ID, *opts.Auth); err != nil {
+			return nil, err
+		}
+	}
+	if feed.Headers == nil {
+		feed.Headers = make(map[string]string)
+	}
+	for _, header := range opts.RemoveHeaders {
+		delete(feed.Headers, header)
+	}
+	for header, value := range opts.SetHeaders {
+		if feed.Headers[header], err = s.encryptHeader(feed.ID, header, value); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ProxyURL != nil {
+		if feed.ProxyURL, err = s.encryptProxy(feed.ID, *opts.ProxyURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.feedRepo.UpdateRequestOptions(ctx, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (s *FeedService) encryptAuth(feedID uuid.UUID, auth domain.FeedAuth) (domain.FeedAuth, error) {
+	secret, err := s.secrets.Encrypt(auth.Secret, domain.SecretScope(feedID, domain.SecretColumnAuth))
+	if err != nil {
+		return auth, fmt.Errorf("failed to encrypt feed credentials: %w", err)
+	}
+	auth.Secret = secret
+	return auth, nil
+}
+
+// encryptHeader encrypts the value of a header that carries a credential
+// and returns other values as they are.
+func (s *FeedService) encryptHeader(feedID uuid.UUID, name, value string) (string, error) {
+	if !domain.IsSensitiveHeader(name) {
+		return value, nil
+	}
+	encrypted, err := s.secrets.Encrypt(value, domain.HeaderSecretScope(feedID, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt header %s: %w", name, err)
+	}
+	return encrypted, nil
+}
+
+func (s *FeedService) encryptProxy(feedID uuid.UUID, proxyURL string) (string, error) {
+	if proxyURL == domain.ProxyDirect {
+		return proxyURL, nil
+	}
+	encrypted, err := s.secrets.Encrypt(proxyURL, domain.SecretScope(feedID, domain.SecretColumnProxy))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt proxy URL: %w", err)
+	}
+	return encrypted, nil
+}
+
+// RotateSecrets re-encrypts the stored secrets of every feed under the
+// current key: credentials, credential headers and proxy URLs, including
+// any stored before encryption was enabled. It returns how many feeds were
+// rewritten.
+func (s *FeedService) RotateSecrets(ctx context.Context) (int, error) {
+	feeds, err := s.feedRepo.ListAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, feed := range feeds {
+		changed, err := s.reencryptFeed(feed)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to re-encrypt secrets of feed %s: %w", feed.Name, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := s.feedRepo.UpdateRequestOptions(ctx, feed); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// reencryptFeed re-encrypts the secrets of feed in place and reports
+// whether it has any.
+func (s *FeedService) reencryptFeed(feed *domain.Feed) (bool, error) {
+	changed := false
+	reencrypt := func(value *string, scope string) error {
+		if *value == "" {
+			return nil
+		}
+		encrypted, err := s.secrets.Reencrypt(*value, scope)
+		if err != nil {
+			return err
+		}
+		*value = encrypted
+		changed = true
+		return nil
+	}
+
+	if err := reencrypt(&feed.Auth.Secret, domain.SecretScope(feed.ID, domain.SecretColumnAuth)); err != nil {
+		return false, err
+	}
+	for name, value := range feed.Headers {
+		if !domain.IsSensitiveHeader(name) {
+			continue
+		}
+		if err := reencrypt(&value, domain.HeaderSecretScope(feed.ID, name)); err != nil {
+			return false, err
+		}
+		feed.Headers[name] = value
+	}
+	if feed.ProxyURL != domain.ProxyDirect {
+		if err := reencrypt(&feed.ProxyURL, domain.SecretScope(feed.ID, domain.SecretColumnProxy)); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// ValidateFeed fetches and parses the feed without persisting anything,
+// including the cache validators: storing them before the first real fetch
+// would make the aggregator receive 304 and never save the initial items.
+func (s *FeedService) ValidateFeed(ctx context.Context, feed *domain.Feed) (*domain.ParsedFeed, error) {
+	result, err := s.rssFetcher.Fetch(ctx, feed.FetchRequest())
+	if err != nil {
+		return nil, fmt.Errorf("feed validation failed for %s: %w", feed.URL, err)
+	}
+	if result.Feed == nil {
+		return nil, fmt.Errorf("feed validation failed for %s: empty response", feed.URL)
+	}
+	return result.Feed, nil
+}
+
+func (s *FeedService) DiscoverFeeds(ctx context.Context, url string) ([]string, error) {
+	if url == "" {
+		return nil, fmt.Errorf("url cannot be empty")
+	}
+
+	return s.discover(ctx, &domain.FetchRequest{URL: url})
+}
+
+func (s *FeedService) discover(ctx context.Context, pageReq *domain.FetchRequest) ([]string, error) {
+	candidates, err := s.discoverer.Discover(ctx, pageReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover feeds: %w", err)
+	}
+	return candidates, nil
 }
 
 func (s *FeedService) ListFeeds(ctx context.Context, limit int) ([]*domain.Feed, error) {
@@ -43,3 +310,40 @@ func (s *FeedService) DeleteFeed(ctx context.Context, name string) error {
 	}
 	return s.feedRepo.Delete(ctx, name)
 }
+
+func (s *FeedService) ListFeedsByStatus(ctx context.Context, status domain.FeedStatus) ([]*domain.Feed, error) {
+	return s.feedRepo.ListByStatus(ctx, status)
+}
+
+// SetFeedStatus lets an operator pause a feed or put a paused, gone or
+// erroring feed back into rotation.
+func (s *FeedService) SetFeedStatus(ctx context.Context, name string, status domain.FeedStatus) error {
+	if name == "" {
+		return fmt.Errorf("feed name cannot be empty")
+	}
+	if err := s.feedRepo.UpdateStatus(ctx, name, status); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("feed not found: %s", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// SetFeedInterval overrides the publisher's polling hints for a feed with a
+// fixed interval. A zero interval goes back to following the hints.
+func (s *FeedService) SetFeedInterval(ctx context.Context, name string, interval time.Duration) error {
+	if name == "" {
+		return fmt.Errorf("feed name cannot be empty")
+	}
+	if interval < 0 {
+		return fmt.Errorf("interval cannot be negative")
+	}
+	if err := s.feedRepo.UpdateIntervalOverride(ctx, name, interval); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("feed not found: %s", name)
+		}
+		return err
+	}
+	return nil
+}
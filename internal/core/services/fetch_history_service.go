@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+
+	"rsshub/internal/domain"
+	"rsshub/internal/ports"
+)
+
+type FetchHistoryService struct {
+	fetchRunRepo ports.FetchRunRepository
+}
+
+func NewFetchHistoryService(fetchRunRepo ports.FetchRunRepository) *FetchHistoryService {
+	return &FetchHistoryService{
+		fetchRunRepo: fetchRunRepo,
+	}
+}
+
+func (s *FetchHistoryService) GetHistory(ctx context.Context, filter domain.FetchRunFilter) ([]*domain.FetchRun, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	return s.fetchRunRepo.List(ctx, filter)
+}
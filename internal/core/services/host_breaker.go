@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"rsshub/internal/domain"
+	"rsshub/internal/ports"
+)
+
+// HostBreaker wraps a fetcher with a circuit breaker per host. After
+// threshold consecutive host failures the breaker opens and fetches to the
+// host fail at once for cooldown. Then a single probe is let through: if
+// it succeeds the breaker closes, otherwise it opens again.
+type HostBreaker struct {
+	next      ports.RSSFetcher
+	threshold int
+	cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostBreaker
+}
+
+type hostBreaker struct {
+	state     domain.BreakerState
+	failures  int
+	openUntil time.Time
+}
+
+func NewHostBreaker(next ports.RSSFetcher, threshold int, cooldown time.Duration) *HostBreaker {
+	return &HostBreaker{
+		next:      next,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		hosts:     make(map[string]*hostBreaker),
+	}
+}
+
+func (b *HostBreaker) Fetch(ctx context.Context, req *domain.FetchRequest) (*domain.FetchResult, error) {
+	host := domain.HostOf(req.URL)
+	if until, open := b.allow(host); open {
+		return nil, &domain.CircuitOpenError{Host: host, Until: until}
+	}
+
+	result, err := b.next.Fetch(ctx, req)
+	b.record(host, err)
+	return result, err
+}
+
+// allow decides whether a fetch to host may go ahead. Once the cooldown
+// has passed the first caller becomes the half-open probe; everyone else
+// keeps being turned away until the probe reports back.
+func (b *HostBreaker) allow(host string) (time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.hosts[host]
+	if !ok {
+		breaker = &hostBreaker{state: domain.BreakerClosed}
+		b.hosts[host] = breaker
+	}
+
+	switch breaker.state {
+	case domain.BreakerOpen:
+		if time.Now().Before(breaker.openUntil) {
+			return breaker.openUntil, true
+		}
+		breaker.state = domain.BreakerHalfOpen
+		return time.Time{}, false
+	case domain.BreakerHalfOpen:
+		return time.Now().Add(b.cooldown), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func (b *HostBreaker) record(host string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker := b.hosts[host]
+	switch {
+	case isHostFailure(err):
+		breaker.failures++
+		if breaker.state == domain.BreakerHalfOpen || breaker.failures >= b.threshold {
+			breaker.state = domain.BreakerOpen
+			breaker.openUntil = time.Now().Add(b.cooldown)
+		}
+	case breaker.state == domain.BreakerHalfOpen && isSkipped(err):
+		// The probe never reached the host, so let the next fetch try again.
+		breaker.state = domain.BreakerOpen
+	default:
+		breaker.state = domain.BreakerClosed
+		breaker.failures = 0
+	}
+}
+
+// isHostFailure reports whether err says the host itself is unwell: it
+// could not be reached, the TLS handshake failed, the request timed out or
+// it answered with a server error. Parse errors, decode errors and local
+// configuration errors concern one feed, not the host. Unknown hosts are
+// left to the feed's own gone accounting, and throttling to the host
+// limiter.
+func isHostFailure(err error) bool {
+	if err == nil || isSkipped(err) || errors.Is(err, domain.ErrHostNotFound) {
+		return false
+	}
+
+	var statusErr *domain.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode == http.StatusServiceUnavailable {
+			return statusErr.RetryAfter == 0
+		}
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	return errors.As(err, &certErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr)
+}
+
+// isSkipped reports whether the fetch was given up before reaching the host.
+func isSkipped(err error) bool {
+	var throttledErr *domain.HostThrottledError
+	return errors.As(err, &throttledErr) ||
+		errors.Is(err, context.Canceled)
+}
+
+// HostStates reports the breaker state of every host fetched so far, merged
+// with the state kept by the wrapped fetcher.
+func (b *HostBreaker) HostStates() []domain.HostState {
+	var inner []domain.HostState
+	if reporter, ok := b.next.(ports.HostStateReporter); ok {
+		inner = reporter.HostStates()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	byHost := make(map[string]domain.HostState, len(b.hosts))
+	for _, state := range inner {
+		byHost[state.Host] = state
+	}
+	for host, breaker := range b.hosts {
+		state, ok := byHost[host]
+		if !ok {
+			state = domain.HostState{Host: host, UpdatedAt: now}
+		}
+		state.Breaker = breaker.state
+		state.BreakerFailures = breaker.failures
+		if breaker.state == domain.BreakerOpen {
+			openUntil := breaker.openUntil
+			state.BreakerOpenUntil = &openUntil
+		}
+		byHost[host] = state
+	}
+
+	states := make([]domain.HostState, 0, len(byHost))
+	for _, state := range byHost {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Host < states[j].Host })
+	return states
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"rsshub/internal/domain"
+	"rsshub/internal/ports"
+)
+
+// defaultThrottlePause is how long a host is paused after a 429 that did
+// not say how long to wait.
+const defaultThrottlePause = time.Minute
+
+// HostLimiter wraps a fetcher so that all workers together open at most
+// maxConcurrent connections to a host and start requests to it at least
+// minDelay apart. A host that answers 429 or 503 with Retry-After is not
+// fetched again until that delay has passed.
+type HostLimiter struct {
+	next          ports.RSSFetcher
+	maxConcurrent int
+	minDelay      time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimit
+}
+
+type hostLimit struct {
+	slots       chan struct{}
+	nextStart   time.Time
+	pausedUntil time.Time
+	pauseReason string
+	inFlight    int
+	requests    int64
+}
+
+func NewHostLimiter(next ports.RSSFetcher, maxConcurrent int, minDelay time.Duration) *HostLimiter {
+	return &HostLimiter{
+		next:          next,
+		maxConcurrent: max(maxConcurrent, 1),
+		minDelay:      minDelay,
+		hosts:         make(map[string]*hostLimit),
+	}
+}
+
+func (l *HostLimiter) Fetch(ctx context.Context, req *domain.FetchRequest) (*domain.FetchResult, error) {
+	host := domain.HostOf(req.URL)
+	limit := l.host(host)
+
+	if until, paused := l.pausedUntil(limit); paused {
+		return nil, &domain.HostThrottledError{Host: host, Until: until}
+	}
+
+	select {
+	case limit.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-limit.slots }()
+
+	// Another worker may have been told to back off while this one waited.
+	if until, paused := l.pausedUntil(limit); paused {
+		return nil, &domain.HostThrottledError{Host: host, Until: until}
+	}
+
+	if err := l.waitTurn(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	if until, paused := l.pausedUntil(limit); paused {
+		l.release(limit)
+		return nil, &domain.HostThrottledError{Host: host, Until: until}
+	}
+
+	result, err := l.next.Fetch(ctx, req)
+	l.finish(limit, err)
+	return result, err
+}
+
+func (l *HostLimiter) host(host string) *hostLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.hosts[host]
+	if !ok {
+		limit = &hostLimit{slots: make(chan struct{}, l.maxConcurrent)}
+		l.hosts[host] = limit
+	}
+	return limit
+}
+
+func (l *HostLimiter) pausedUntil(limit *hostLimit) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return limit.pausedUntil, time.Now().Before(limit.pausedUntil)
+}
+
+// waitTurn reserves the next start time for the host and sleeps until it.
+func (l *HostLimiter) waitTurn(ctx context.Context, limit *hostLimit) error {
+	l.mu.Lock()
+	start := time.Now()
+	if limit.nextStart.After(start) {
+		start = limit.nextStart
+	}
+	limit.nextStart = start.Add(l.minDelay)
+	limit.inFlight++
+	limit.requests++
+	l.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(limit)
+		return ctx.Err()
+	}
+}
+
+// release undoes the counts waitTurn took for a request that was not sent.
+func (l *HostLimiter) release(limit *hostLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit.inFlight--
+	limit.requests--
+}
+
+func (l *HostLimiter) finish(limit *hostLimit, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit.inFlight--
+
+	var statusErr *domain.StatusError
+	if !errors.As(err, &statusErr) {
+		return
+	}
+
+	pause := statusErr.RetryAfter
+	switch statusErr.StatusCode {
+	case http.StatusTooManyRequests:
+		if pause == 0 {
+			pause = defaultThrottlePause
+		}
+	case http.StatusServiceUnavailable:
+	default:
+		return
+	}
+	if pause <= 0 {
+		return
+	}
+
+	if until := time.Now().Add(pause); until.After(limit.pausedUntil) {
+		limit.pausedUntil = until
+		limit.pauseReason = http.StatusText(statusErr.StatusCode)
+	}
+}
+
+// HostStates reports the current state of every host fetched so far.
+func (l *HostLimiter) HostStates() []domain.HostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	states := make([]domain.HostState, 0, len(l.hosts))
+	for host, limit := range l.hosts {
+		state := domain.HostState{
+			Host:      host,
+			InFlight:  limit.inFlight,
+			Requests:  limit.requests,
+			UpdatedAt: now,
+		}
+		if now.Before(limit.pausedUntil) {
+			pausedUntil := limit.pausedUntil
+			state.PausedUntil = &pausedUntil
+			state.PauseReason = limit.pauseReason
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Host < states[j].Host })
+	return states
+}
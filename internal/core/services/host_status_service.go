@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"rsshub/internal/domain"
+	"rsshub/internal/ports"
+)
+
+// HostStatusService reads the per-host state last published by the
+// running aggregator.
+type HostStatusService struct {
+	hostStateRepo ports.HostStateRepository
+}
+
+func NewHostStatusService(hostStateRepo ports.HostStateRepository) *HostStatusService {
+	return &HostStatusService{
+		hostStateRepo: hostStateRepo,
+	}
+}
+
+func (s *HostStatusService) ListHostStates(ctx context.Context) ([]domain.HostState, error) {
+	return s.hostStateRepo.List(ctx)
+}
@@ -15,12 +15,24 @@ type Article struct {
 	PublishedAt *time.Time
 	Description string
 	FeedID      uuid.UUID
+	GUID        string
+	Author      string
+	Categories  []string
+	Content     string
+	Enclosures  []Enclosure
+	DedupKey    string
+	Fingerprint uint64
+	StoryID     uuid.UUID
+	// PublishedAtInferred is set when the feed gave no usable date and
+	// PublishedAt is a best guess.
+	PublishedAtInferred bool
 }
 
 func NewArticle(title, link, description string, publishedAt *time.Time, feedID uuid.UUID) *Article {
 	now := time.Now()
+	id := uuid.New()
 	return &Article{
-		ID:          uuid.New(),
+		ID:          id,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		Title:       title,
@@ -28,5 +40,53 @@ func NewArticle(title, link, description string, publishedAt *time.Time, feedID
 		Description: description,
 		PublishedAt: publishedAt,
 		FeedID:      feedID,
+		StoryID:     id,
+		Fingerprint: SimHash(title, description),
 	}
 }
+
+// JoinStory puts the article into the story of the closest candidate from
+// another feed within StoryMaxDistance, or starts a new story of its own.
+// Candidates from the same feed are ignored: recurring posts such as
+// numbered daily updates look alike but are different stories.
+func (a *Article) JoinStory(candidates []StoryFingerprint) {
+	a.StoryID = a.ID
+	if a.Fingerprint == 0 {
+		return
+	}
+
+	best := StoryMaxDistance + 1
+	for _, candidate := range candidates {
+		if candidate.Fingerprint == 0 || candidate.ArticleID == a.ID || candidate.FeedID == a.FeedID {
+			continue
+		}
+		if distance := HammingDistance(a.Fingerprint, candidate.Fingerprint); distance < best {
+			best = distance
+			a.StoryID = candidate.StoryID
+		}
+	}
+}
+
+func (a *Article) StoryFingerprint() StoryFingerprint {
+	return StoryFingerprint{
+		ArticleID:   a.ID,
+		FeedID:      a.FeedID,
+		StoryID:     a.StoryID,
+		Fingerprint: a.Fingerprint,
+	}
+}
+
+func NewArticleFromItem(item *FeedItem, feedID uuid.UUID, canonicalizer *URLCanonicalizer) *Article {
+	link := canonicalizer.Canonicalize(item.Link)
+	article := NewArticle(item.Title, link, item.Description, nil, feedID)
+	publishedAt, inferred := InferDate(item.PubDate, article.CreatedAt)
+	article.PublishedAt = &publishedAt
+	article.PublishedAtInferred = inferred
+	article.GUID = item.GUID
+	article.Author = item.Author
+	article.Categories = item.Categories
+	article.Content = item.Content
+	article.Enclosures = item.Enclosures
+	article.DedupKey = item.DedupKey(canonicalizer)
+	return article
+}
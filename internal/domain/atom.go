@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type AtomFeed struct {
+	XMLName  xml.Name     `xml:"feed"`
+	Title    AtomText     `xml:"title"`
+	Subtitle AtomText     `xml:"subtitle"`
+	Links    []AtomLink   `xml:"link"`
+	Updated  string       `xml:"updated"`
+	Authors  []AtomPerson `xml:"author"`
+	Entries  []AtomEntry  `xml:"entry"`
+	SyndicationElements
+}
+
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      AtomText       `xml:"title"`
+	Links      []AtomLink     `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Summary    AtomText       `xml:"summary"`
+	Content    AtomText       `xml:"content"`
+	Authors    []AtomPerson   `xml:"author"`
+	Categories []AtomCategory `xml:"category"`
+	MediaElements
+}
+
+type AtomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+	URI   string `xml:"uri"`
+}
+
+type AtomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// AtomText is an Atom text construct. For type="xhtml" the payload is a
+// nested <div>, so the raw inner XML is kept instead of the character data.
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Body  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// String returns the markup of the construct: the content of the xhtml
+// wrapper <div>, or the decoded body for text and html.
+func (t AtomText) String() string {
+	if t.Type == "xhtml" {
+		return unwrapXHTMLDiv(strings.TrimSpace(t.Inner))
+	}
+	return strings.TrimSpace(t.Body)
+}
+
+// Text returns the construct as plain text, for titles and summaries.
+func (t AtomText) Text() string {
+	if t.Type == "html" || t.Type == "xhtml" {
+		return htmlToText(t.String())
+	}
+	return strings.TrimSpace(t.Body)
+}
+
+// unwrapXHTMLDiv strips the <div xmlns="http://www.w3.org/1999/xhtml">
+// that the Atom spec requires around xhtml content.
+func unwrapXHTMLDiv(inner string) string {
+	var div struct {
+		XMLName xml.Name
+		Inner   string `xml:",innerxml"`
+	}
+	if err := xml.Unmarshal([]byte(inner), &div); err != nil || div.XMLName.Local != "div" {
+		return inner
+	}
+	return strings.TrimSpace(div.Inner)
+}
+
+// htmlToText drops the tags of an HTML fragment, decodes its entities and
+// collapses whitespace.
+func htmlToText(fragment string) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(text.String()), " ")
+		case html.TextToken:
+			text.Write(tokenizer.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			text.WriteByte(' ')
+		}
+	}
+}
+
+func (f *AtomFeed) ToParsedFeed() *ParsedFeed {
+	parsed := &ParsedFeed{
+		Title:       f.Title.Text(),
+		Link:        alternateLink(f.Links),
+		Description: f.Subtitle.Text(),
+		Items:       make([]FeedItem, 0, len(f.Entries)),
+		Schedule:    ScheduleHints{UpdatePeriod: f.updateInterval()},
+	}
+
+	for _, entry := range f.Entries {
+		description := entry.Summary.Text()
+		if description == "" {
+			description = entry.Content.Text()
+		}
+
+		// Entries without their own author inherit the feed-level one.
+		authors := entry.Authors
+		if len(authors) == 0 {
+			authors = f.Authors
+		}
+
+		// Atom carries enclosures as rel="enclosure" links; YouTube-style
+		// feeds add Media RSS elements on top.
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				entry.Enclosures = append(entry.Enclosures, RSSEnclosure{
+					URL:    link.Href,
+					Type:   link.Type,
+					Length: link.Length,
+				})
+			}
+		}
+
+		categories := make([]string, 0, len(entry.Categories))
+		for _, category := range entry.Categories {
+			categories = append(categories, category.Term)
+		}
+
+		parsed.Items = append(parsed.Items, FeedItem{
+			GUID:        strings.TrimSpace(entry.ID),
+			Title:       entry.Title.Text(),
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			PubDate:     entry.PubDate(),
+			Author:      joinAuthorNames(authors),
+			Categories:  normalizeCategories(categories),
+			Content:     entry.Content.String(),
+			Enclosures:  entry.toEnclosures(),
+		})
+	}
+
+	return parsed
+}
+
+func (e *AtomEntry) PubDate() string {
+	return firstNonEmpty(e.Published, e.Updated)
+}
+
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func joinAuthorNames(authors []AtomPerson) string {
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are query parameters that only identify the
+// referrer or campaign and never change which document a URL points to.
+// A trailing "*" matches any suffix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"yclid",
+	"msclkid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_hsenc",
+	"_hsmi",
+	"ref",
+	"ref_src",
+	"ref_url",
+}
+
+// URLCanonicalizer rewrites URLs into one form, so that equivalent URLs
+// compare equal. Article links and feed URLs are stored in that form and
+// article dedup keys are derived from it.
+type URLCanonicalizer struct {
+	strippedParams []string
+}
+
+func NewURLCanonicalizer(strippedParams []string) *URLCanonicalizer {
+	normalized := make([]string, 0, len(strippedParams))
+	for _, param := range strippedParams {
+		if param = strings.ToLower(strings.TrimSpace(param)); param != "" {
+			normalized = append(normalized, param)
+		}
+	}
+	return &URLCanonicalizer{strippedParams: normalized}
+}
+
+// WithParams returns a canonicalizer that strips extra on top of the
+// receiver's parameters. It is used to layer per-feed settings over the
+// global list.
+func (c *URLCanonicalizer) WithParams(extra []string) *URLCanonicalizer {
+	if len(extra) == 0 {
+		return c
+	}
+	params := append(append([]string{}, c.strippedParams...), extra...)
+	return NewURLCanonicalizer(params)
+}
+
+// Canonicalize lowercases the scheme and host, drops default ports,
+// fragments, tracking parameters and trailing slashes, and sorts the
+// remaining query parameters. Values that are not absolute URLs are
+// returned trimmed but otherwise untouched.
+func (c *URLCanonicalizer) Canonicalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return raw
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if port := parsed.Port(); (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		parsed.Host = parsed.Hostname()
+	}
+
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	if trimmed := strings.TrimRight(parsed.Path, "/"); trimmed != parsed.Path || trimmed == "" {
+		parsed.Path = trimmed
+		parsed.RawPath = strings.TrimRight(parsed.RawPath, "/")
+		if parsed.Path == "" {
+			parsed.Path = "/"
+		}
+	}
+
+	query := parsed.Query()
+	for key := range query {
+		if c.isStripped(key) {
+			query.Del(key)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// Key is the scheme-less canonical form, so that http and https variants of
+// the same link compare equal.
+func (c *URLCanonicalizer) Key(raw string) string {
+	canonical := c.Canonicalize(raw)
+	if i := strings.Index(canonical, "://"); i >= 0 {
+		return canonical[i+1:]
+	}
+	return canonical
+}
+
+func (c *URLCanonicalizer) isStripped(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range c.strippedParams {
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
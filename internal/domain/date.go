@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateLayouts are tried against a normalized date: weekdays and commas
+// removed, month names translated to English abbreviations, zone names
+// replaced by numeric offsets and offsets written without a colon.
+var dateLayouts = []string{
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006 3:04 PM -0700",
+	"2 Jan 2006 3:04 PM",
+	"2 Jan 2006",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"Jan 2 2006 15:04:05 -0700",
+	"Jan 2 2006 15:04 -0700",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006 15:04",
+	"Jan 2 2006 3:04 PM -0700",
+	"Jan 2 2006 3:04 PM",
+	"Jan 2 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"Jan 2 15:04:05 2006",
+	"2006-1-2 15:04:05 -0700",
+	"2006-1-2 15:04 -0700",
+	"2006-1-2 15:04:05",
+	"2006-1-2 15:04",
+	"2006-1-2",
+	"2006/1/2 15:04:05 -0700",
+	"2006/1/2 15:04:05",
+	"2006/1/2",
+	"2.1.2006 15:04:05 -0700",
+	"2.1.2006 15:04 -0700",
+	"2.1.2006 15:04:05",
+	"2.1.2006 15:04",
+	"2.1.2006",
+}
+
+// zoneOffsets maps the zone abbreviations publishers put in dates to their
+// offsets. time.Parse accepts any abbreviation but silently treats unknown
+// ones as UTC, so they are resolved here instead.
+var zoneOffsets = map[string]string{
+	"UT": "+0000", "UTC": "+0000", "GMT": "+0000", "Z": "+0000", "WET": "+0000",
+	"WEST": "+0100", "BST": "+0100", "CET": "+0100", "MET": "+0100",
+	"CEST": "+0200", "MEST": "+0200", "EET": "+0200", "SAST": "+0200",
+	"EEST": "+0300", "MSK": "+0300", "TRT": "+0300",
+	"MSD": "+0400", "GST": "+0400", "SAMT": "+0400",
+	"PKT": "+0500", "YEKT": "+0500", "ALMT": "+0500", "AQTT": "+0500", "UZT": "+0500",
+	"IST":  "+0530",
+	"OMST": "+0600", "BDT": "+0600",
+	"ICT": "+0700", "WIB": "+0700", "KRAT": "+0700", "NOVT": "+0700",
+	"CST": "-0600", "CDT": "-0500",
+	"EST": "-0500", "EDT": "-0400",
+	"MST": "-0700", "MDT": "-0600",
+	"PST": "-0800", "PDT": "-0700",
+	"AKST": "-0900", "AKDT": "-0800",
+	"HST": "-1000",
+	"AST": "-0400", "ADT": "-0300",
+	"NST": "-0330", "NDT": "-0230",
+	"SGT": "+0800", "HKT": "+0800", "AWST": "+0800", "PHT": "+0800",
+	"JST": "+0900", "KST": "+0900",
+	"ACST": "+0930", "ACDT": "+1030",
+	"AEST": "+1000", "AEDT": "+1100",
+	"NZST": "+1200", "NZDT": "+1300",
+}
+
+// monthNames maps lower-cased full and abbreviated month names, English and
+// localized, to the abbreviations time.Parse understands.
+var monthNames = buildMonthNames(map[string][]string{
+	"Jan": {"january", "jan", "января", "январь", "янв", "қаңтар", "januar", "janv", "janvier", "enero", "ene", "gennaio", "gen", "stycznia", "sty", "ocak"},
+	"Feb": {"february", "feb", "февраля", "февраль", "фев", "ақпан", "februar", "févr", "février", "fevr", "febrero", "febbraio", "lutego", "lut", "şubat"},
+	"Mar": {"march", "mar", "марта", "март", "мар", "наурыз", "märz", "mär", "mars", "marzo", "marca", "mart"},
+	"Apr": {"april", "apr", "апреля", "апрель", "апр", "сәуір", "avr", "avril", "abril", "abr", "aprile", "kwietnia", "kwi", "nisan"},
+	"May": {"may", "мая", "май", "мамыр", "mai", "mayo", "maggio", "mag", "maja", "mayıs"},
+	"Jun": {"june", "jun", "июня", "июнь", "июн", "маусым", "juni", "juin", "junio", "giugno", "giu", "czerwca", "cze", "haziran"},
+	"Jul": {"july", "jul", "июля", "июль", "июл", "шілде", "juli", "juil", "juillet", "julio", "luglio", "lug", "lipca", "lip", "temmuz"},
+	"Aug": {"august", "aug", "августа", "август", "авг", "тамыз", "août", "aout", "agosto", "ago", "sierpnia", "sie", "ağustos"},
+	"Sep": {"september", "sep", "sept", "сентября", "сентябрь", "сен", "сент", "қыркүйек", "septembre", "septiembre", "settembre", "set", "września", "wrz", "eylül"},
+	"Oct": {"october", "oct", "октября", "октябрь", "окт", "қазан", "oktober", "okt", "octobre", "octubre", "ottobre", "ott", "października", "paź", "ekim"},
+	"Nov": {"november", "nov", "ноября", "ноябрь", "ноя", "қараша", "novembre", "noviembre", "listopada", "lis", "kasım"},
+	"Dec": {"december", "dec", "декабря", "декабрь", "дек", "желтоқсан", "dezember", "dez", "déc", "décembre", "diciembre", "dic", "dicembre", "grudnia", "gru", "aralık"},
+})
+
+// weekdayNames are dropped before parsing: they carry no information the
+// date does not, and publishers often get them wrong.
+var weekdayNames = toSet(
+	"mon", "monday", "tue", "tues", "tuesday", "wed", "wednesday", "thu", "thur", "thurs", "thursday",
+	"fri", "friday", "sat", "saturday", "sun", "sunday",
+	"пн", "вт", "ср", "чт", "пт", "сб", "вс",
+	"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье",
+	"дүйсенбі", "сейсенбі", "сәрсенбі", "бейсенбі", "жұма", "сенбі", "жексенбі",
+	"mo", "di", "mi", "do", "fr", "sa", "so",
+	"montag", "dienstag", "mittwoch", "donnerstag", "freitag", "samstag", "sonntag",
+	"lun", "mer", "jeu", "ven", "sam", "dim",
+	"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche",
+	"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo",
+)
+
+// dateFillers are connecting words some locales put between date parts.
+var dateFillers = toSet("de", "del", "г", "г.", "года", "at", "um", "à")
+
+var (
+	isoDateTime   = regexp.MustCompile(`^(\d{4}-\d{1,2}-\d{1,2})[Tt]`)
+	gluedOffset   = regexp.MustCompile(`^(\d{1,2}:\d{2}(?::\d{2}(?:[.,]\d+)?)?)([Zz]|[+-]\d{2}(?::?\d{2})?)$`)
+	prefixedZone  = regexp.MustCompile(`^(?:GMT|UTC|UT)([+-]\d{1,2}(?::?\d{2})?)$`)
+	numericOffset = regexp.MustCompile(`^([+-])(\d{1,2}):?(\d{2})?$`)
+	embeddedDate  = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+// ParseDate parses a publication date in any of the formats seen in real
+// feeds. It returns nil when the value cannot be understood.
+func ParseDate(value string) *time.Time {
+	normalized := normalizeDate(value)
+	if normalized == "" {
+		return nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return &t
+		}
+	}
+
+	return nil
+}
+
+// InferDate parses value like ParseDate. When that fails it falls back to a
+// calendar date found anywhere in the value, and then to fallback; the
+// second result reports that the date was inferred rather than parsed.
+func InferDate(value string, fallback time.Time) (time.Time, bool) {
+	if t := ParseDate(value); t != nil {
+		return *t, false
+	}
+
+	if match := embeddedDate.FindString(value); match != "" {
+		if t, err := time.Parse("2006-01-02", match); err == nil {
+			return t, true
+		}
+	}
+
+	return fallback, true
+}
+
+func normalizeDate(value string) string {
+	value = strings.TrimSpace(value)
+	value = isoDateTime.ReplaceAllString(value, "$1 ")
+	value = strings.ReplaceAll(value, ",", " ")
+
+	fields := strings.Fields(value)
+	normalized := make([]string, 0, len(fields)+1)
+	for i, field := range fields {
+		lower := strings.ToLower(field)
+		if dateFillers[lower] || weekdayNames[lower] || (i == 0 && weekdayNames[strings.TrimSuffix(lower, ".")]) {
+			continue
+		}
+		if month, ok := monthNames[strings.TrimSuffix(lower, ".")]; ok {
+			normalized = append(normalized, month)
+			continue
+		}
+		if lower == "am" || lower == "pm" {
+			normalized = append(normalized, strings.ToUpper(field))
+			continue
+		}
+		if match := gluedOffset.FindStringSubmatch(field); match != nil {
+			normalized = append(normalized, match[1], normalizeOffset(match[2]))
+			continue
+		}
+		if match := prefixedZone.FindStringSubmatch(field); match != nil {
+			normalized = append(normalized, normalizeOffset(match[1]))
+			continue
+		}
+		if offset, ok := zoneOffsets[strings.ToUpper(field)]; ok {
+			normalized = append(normalized, offset)
+			continue
+		}
+		if i > 0 && numericOffset.MatchString(field) {
+			normalized = append(normalized, normalizeOffset(field))
+			continue
+		}
+		normalized = append(normalized, field)
+	}
+
+	return strings.Join(normalized, " ")
+}
+
+// normalizeOffset rewrites Z, +3, +03, +03:00 and +0300 as +0300.
+func normalizeOffset(offset string) string {
+	if offset == "Z" || offset == "z" {
+		return "+0000"
+	}
+	match := numericOffset.FindStringSubmatch(offset)
+	if match == nil {
+		return offset
+	}
+	hours, minutes := match[2], match[3]
+	if len(hours) == 1 {
+		hours = "0" + hours
+	}
+	if minutes == "" {
+		minutes = "00"
+	}
+	return match[1] + hours + minutes
+}
+
+func buildMonthNames(names map[string][]string) map[string]string {
+	months := make(map[string]string)
+	for month, aliases := range names {
+		for _, alias := range aliases {
+			months[alias] = month
+		}
+	}
+	return months
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"RFC 1123", "Mon, 02 Jan 2006 15:04:05 GMT", "2006-01-02T15:04:05Z"},
+		{"RFC 1123 numeric zone", "Mon, 02 Jan 2006 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"single-digit day", "Tue, 5 Mar 2024 08:30:00 +0000", "2024-03-05T08:30:00Z"},
+		{"missing seconds", "Tue, 05 Mar 2024 08:30 +0000", "2024-03-05T08:30:00Z"},
+		{"missing weekday", "05 Mar 2024 08:30:00 +0100", "2024-03-05T08:30:00+01:00"},
+		{"full weekday", "Tuesday, 05 March 2024 08:30:00 GMT", "2024-03-05T08:30:00Z"},
+		{"two-digit year", "Tue, 05 Mar 24 08:30:00 +0000", "2024-03-05T08:30:00Z"},
+		{"wrong weekday", "Fri, 05 Mar 2024 08:30:00 +0000", "2024-03-05T08:30:00Z"},
+		{"EST", "Tue, 05 Mar 2024 08:30:00 EST", "2024-03-05T08:30:00-05:00"},
+		{"PDT", "Tue, 05 Mar 2024 08:30:00 PDT", "2024-03-05T08:30:00-07:00"},
+		{"MSK", "Tue, 05 Mar 2024 08:30:00 MSK", "2024-03-05T08:30:00+03:00"},
+		{"UT", "Tue, 05 Mar 2024 08:30:00 UT", "2024-03-05T08:30:00Z"},
+		{"GMT with offset", "Tue, 05 Mar 2024 08:30:00 GMT+3", "2024-03-05T08:30:00+03:00"},
+		{"offset with colon", "Tue, 05 Mar 2024 08:30:00 +03:00", "2024-03-05T08:30:00+03:00"},
+		{"no zone", "Tue, 05 Mar 2024 08:30:00", "2024-03-05T08:30:00Z"},
+		{"extra whitespace", "  Tue,  05 Mar 2024   08:30:00 +0000 ", "2024-03-05T08:30:00Z"},
+		{"RFC 3339", "2024-03-05T08:30:00Z", "2024-03-05T08:30:00Z"},
+		{"RFC 3339 offset", "2024-03-05T08:30:00+06:00", "2024-03-05T08:30:00+06:00"},
+		{"ISO 8601 offset without colon", "2024-03-05T08:30:00+0600", "2024-03-05T08:30:00+06:00"},
+		{"ISO 8601 hour-only offset", "2024-03-05T08:30:00+06", "2024-03-05T08:30:00+06:00"},
+		{"ISO 8601 fractional seconds", "2024-03-05T08:30:00.123456Z", "2024-03-05T08:30:00.123456Z"},
+		{"ISO 8601 without seconds", "2024-03-05T08:30Z", "2024-03-05T08:30:00Z"},
+		{"ISO 8601 lower-case separator", "2024-03-05t08:30:00z", "2024-03-05T08:30:00Z"},
+		{"ISO 8601 local time", "2024-03-05T08:30:00", "2024-03-05T08:30:00Z"},
+		{"ISO 8601 space separator", "2024-03-05 08:30:00 +0300", "2024-03-05T08:30:00+03:00"},
+		{"date only", "2024-03-05", "2024-03-05T00:00:00Z"},
+		{"slashes", "2024/03/05 08:30:00", "2024-03-05T08:30:00Z"},
+		{"dotted", "05.03.2024 08:30", "2024-03-05T08:30:00Z"},
+		{"US style", "March 5, 2024", "2024-03-05T00:00:00Z"},
+		{"US style with time", "Mar 5, 2024 8:30 PM", "2024-03-05T20:30:00Z"},
+		{"Unix date", "Tue Mar  5 08:30:00 MSK 2024", "2024-03-05T08:30:00+03:00"},
+		{"ANSI C", "Tue Mar  5 08:30:00 2024", "2024-03-05T08:30:00Z"},
+		{"Russian genitive", "5 марта 2024 08:30 +0300", "2024-03-05T08:30:00+03:00"},
+		{"Russian abbreviation", "Вт, 05 мар. 2024 08:30:00 +0300", "2024-03-05T08:30:00+03:00"},
+		{"Kazakh", "5 наурыз 2024 08:30", "2024-03-05T08:30:00Z"},
+		{"German", "Di, 05 März 2024 08:30:00 +0100", "2024-03-05T08:30:00+01:00"},
+		{"French", "mardi 5 mars 2024 08:30", "2024-03-05T08:30:00Z"},
+		{"Spanish", "5 de abril de 2024", "2024-04-05T00:00:00Z"},
+		{"Russian year suffix", "5 марта 2024 г. 08:30", "2024-03-05T08:30:00Z"},
+		{"connecting word", "March 5, 2024 at 8:30 PM EST", "2024-03-05T20:30:00-05:00"},
+		{"empty", "", ""},
+		{"garbage", "yesterday", ""},
+		{"unknown zone", "Tue, 05 Mar 2024 08:30:00 XYZ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDate(tt.value)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("ParseDate(%q) = %v, want nil", tt.value, got)
+				}
+				return
+			}
+
+			want, err := time.Parse(time.RFC3339Nano, tt.want)
+			if err != nil {
+				t.Fatalf("bad expectation %q: %v", tt.want, err)
+			}
+			if got == nil {
+				t.Fatalf("ParseDate(%q) = nil, want %v", tt.value, want)
+			}
+			if !got.Equal(want) {
+				t.Fatalf("ParseDate(%q) = %v, want %v", tt.value, got, want)
+			}
+			_, gotOffset := got.Zone()
+			_, wantOffset := want.Zone()
+			if gotOffset != wantOffset {
+				t.Fatalf("ParseDate(%q) offset = %d, want %d", tt.value, gotOffset, wantOffset)
+			}
+		})
+	}
+}
+
+func TestInferDate(t *testing.T) {
+	fallback := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		value        string
+		want         time.Time
+		wantInferred bool
+	}{
+		{"parsed", "2024-03-05T08:30:00Z", time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC), false},
+		{"embedded date", "posted 2024-03-05 around noon", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), true},
+		{"empty", "", fallback, true},
+		{"garbage", "last Tuesday", fallback, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, inferred := InferDate(tt.value, fallback)
+			if !got.Equal(tt.want) || inferred != tt.wantInferred {
+				t.Fatalf("InferDate(%q) = %v, %v; want %v, %v", tt.value, got, inferred, tt.want, tt.wantInferred)
+			}
+		})
+	}
+}
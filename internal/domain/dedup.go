@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// DedupKey identifies an item within its feed. The publisher's guid is the
+// most reliable identity; without it the canonical link is used, and items
+// that have neither fall back to a hash of title and date.
+func (item *FeedItem) DedupKey(canonicalizer *URLCanonicalizer) string {
+	if guid := strings.TrimSpace(item.GUID); guid != "" {
+		return "guid:" + guid
+	}
+
+	if key := item.LinkDedupKey(canonicalizer); key != "" {
+		return key
+	}
+
+	title := strings.ToLower(strings.Join(strings.Fields(item.Title), " "))
+	sum := sha256.Sum256([]byte(title + "\n" + strings.TrimSpace(item.PubDate)))
+	return "hash:" + hex.EncodeToString(sum[:])
+}
+
+// LinkDedupKey is the key the item would have by its link alone, or empty
+// when it has no link. Articles stored before guids were kept have only
+// this key, so lookups fall back to it for items that have a guid now.
+func (item *FeedItem) LinkDedupKey(canonicalizer *URLCanonicalizer) string {
+	if link := canonicalizer.Key(item.Link); link != "" {
+		return "link:" + link
+	}
+	return ""
+}
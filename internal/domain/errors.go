@@ -2,9 +2,48 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 var (
 	ErrNotFound                 = errors.New("Not Found")
 	ErrAggregatorAlreadyRunning = errors.New("background process is already running")
+	ErrNoFeedFound              = errors.New("no feed found at this URL")
+	ErrHostNotFound             = errors.New("host not found")
 )
+
+// StatusError is returned by fetchers when the server answers with a
+// status code other than 200 or 304. RetryAfter is the delay the server
+// asked for, if any.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// CrossHostRedirectError is recorded when a feed with credentials has
+// moved permanently to another host. The feed keeps its URL; an operator
+// has to add it again by the new one to send the credentials there.
+type CrossHostRedirectError struct {
+	Location string
+}
+
+func (e *CrossHostRedirectError) Error() string {
+	return fmt.Sprintf("feed moved permanently to %s on another host; "+
+		"to send credentials there, add the feed by that URL", e.Location)
+}
+
+// MultipleFeedsError is returned when discovery finds more than one feed and
+// the caller has to pick one of the candidates explicitly.
+type MultipleFeedsError struct {
+	Candidates []string
+}
+
+func (e *MultipleFeedsError) Error() string {
+	return "multiple feeds found: " + strings.Join(e.Candidates, ", ")
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,34 @@ type Feed struct {
 	Name          string
 	URL           string
 	LastFetchedAt *time.Time
+	ETag          string
+	LastModified  string
+	StripParams   []string
+	// RedirectURL and RedirectCount track where the feed has been
+	// permanently redirected to in consecutive fetches.
+	RedirectURL   string
+	RedirectCount int
+	Status        FeedStatus
+	// NotFoundCount counts consecutive fetches that failed with 404 or an
+	// unknown host.
+	NotFoundCount int
+	// ConsecutiveFailures counts fetches that failed since the last
+	// success; NextFetchAt holds the feed back until its backoff expires.
+	ConsecutiveFailures int
+	LastError           string
+	LastErrorAt         *time.Time
+	NextFetchAt         *time.Time
+	// Schedule holds the publisher's polling hints from the last fetch.
+	// A non-zero IntervalOverride set by an operator replaces them.
+	Schedule         ScheduleHints
+	IntervalOverride time.Duration
+	// Auth and Headers are sent with every request for the feed. Headers
+	// override the fetcher's defaults, such as User-Agent and Accept.
+	Auth    FeedAuth
+	Headers map[string]string
+	// ProxyURL routes the feed through its own proxy, or none for
+	// ProxyDirect. Empty uses the configured one.
+	ProxyURL string
 }
 
 func NewFeed(name, url string) *Feed {
@@ -23,6 +52,7 @@ func NewFeed(name, url string) *Feed {
 		UpdatedAt: now,
 		Name:      name,
 		URL:       url,
+		Status:    FeedStatusActive,
 	}
 }
 
@@ -31,3 +61,132 @@ func (f *Feed) MarkAsFetched() {
 	f.LastFetchedAt = &now
 	f.UpdatedAt = now
 }
+
+func (f *Feed) FetchRequest() *FetchRequest {
+	return &FetchRequest{
+		FeedID:       f.ID,
+		URL:          f.URL,
+		ETag:         f.ETag,
+		LastModified: f.LastModified,
+		Auth:         f.Auth,
+		Headers:      f.Headers,
+		ProxyURL:     f.ProxyURL,
+	}
+}
+
+func (f *Feed) SetCacheValidators(etag, lastModified string) {
+	f.ETag = etag
+	f.LastModified = lastModified
+}
+
+// RecordRedirect notes where this fetch was permanently redirected to, or
+// an empty location when it was not. Once the same location has been seen
+// threshold times in a row the feed adopts it as its URL and RecordRedirect
+// returns true. A feed with credentials is not moved to another host, as
+// they would be sent there from then on; it is marked erroring with a
+// CrossHostRedirectError instead, which is also returned.
+func (f *Feed) RecordRedirect(location string, threshold int) (bool, error) {
+	if location == "" || location == f.URL {
+		f.RedirectURL = ""
+		f.RedirectCount = 0
+		return false, nil
+	}
+
+	if location == f.RedirectURL {
+		f.RedirectCount++
+	} else {
+		f.RedirectURL = location
+		f.RedirectCount = 1
+	}
+
+	if threshold <= 0 || f.RedirectCount < threshold {
+		return false, nil
+	}
+
+	if HasCredentials(f.Auth, f.Headers) && HostOf(location) != HostOf(f.URL) {
+		err := &CrossHostRedirectError{Location: location}
+		now := time.Now()
+		f.LastError = err.Error()
+		f.LastErrorAt = &now
+		if f.Status == FeedStatusActive {
+			f.Status = FeedStatusErroring
+		}
+		return false, err
+	}
+
+	f.URL = location
+	f.RedirectURL = ""
+	f.RedirectCount = 0
+	return true, nil
+}
+
+// RecordFetchSuccess clears the failure state left by earlier fetches. The
+// last error is kept for reference.
+func (f *Feed) RecordFetchSuccess() {
+	f.NotFoundCount = 0
+	f.ConsecutiveFailures = 0
+	f.NextFetchAt = nil
+	if f.Status == FeedStatusErroring {
+		f.Status = FeedStatusActive
+	}
+}
+
+// RecordFetchFailure records a failed fetch and backs the feed off
+// according to policy. A 410 marks the feed gone at once; 404s and unknown
+// hosts do so after policy.GoneThreshold consecutive failures. It returns
+// true when the feed has just become gone.
+func (f *Feed) RecordFetchFailure(err error, policy FeedPolicy) bool {
+	now := time.Now()
+	f.UpdatedAt = now
+	f.ConsecutiveFailures++
+	f.LastError = err.Error()
+	f.LastErrorAt = &now
+	nextFetchAt := now.Add(policy.Backoff(f.ConsecutiveFailures))
+	f.NextFetchAt = &nextFetchAt
+
+	if f.Status == FeedStatusGone || f.Status == FeedStatusPaused {
+		return false
+	}
+
+	var statusErr *StatusError
+	isStatus := errors.As(err, &statusErr)
+	switch {
+	case isStatus && statusErr.StatusCode == 410:
+		f.Status = FeedStatusGone
+	case isStatus && statusErr.StatusCode == 404, errors.Is(err, ErrHostNotFound):
+		f.NotFoundCount++
+		if policy.GoneThreshold > 0 && f.NotFoundCount >= policy.GoneThreshold {
+			f.Status = FeedStatusGone
+		} else {
+			f.Status = FeedStatusErroring
+		}
+	default:
+		f.NotFoundCount = 0
+		f.Status = FeedStatusErroring
+	}
+
+	return f.Status == FeedStatusGone
+}
+
+// scheduleSlack is taken off scheduled fetch times so that a feed due at
+// about the next aggregator tick is not pushed back a whole tick.
+const scheduleSlack = 30 * time.Second
+
+// ScheduleNextFetch sets NextFetchAt after a successful fetch at now, given
+// the aggregator's interval. Without an override or publisher hints that
+// ask for less than that, the feed is simply due on the next tick.
+func (f *Feed) ScheduleNextFetch(now time.Time, interval time.Duration) {
+	var next time.Time
+	switch {
+	case f.IntervalOverride > 0:
+		next = now.Add(f.IntervalOverride)
+	case f.Schedule.MinInterval() > interval || f.Schedule.Skips(now.Add(interval)):
+		next = f.Schedule.NextFetch(now, interval)
+	default:
+		f.NextFetchAt = nil
+		return
+	}
+
+	next = next.Add(-scheduleSlack)
+	f.NextFetchAt = &next
+}
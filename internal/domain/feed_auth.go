@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AuthType is how a feed authenticates its requests.
+type AuthType string
+
+const (
+	AuthNone   AuthType = ""
+	AuthBasic  AuthType = "basic"
+	AuthBearer AuthType = "bearer"
+	AuthCookie AuthType = "cookie"
+)
+
+// FeedAuth holds the credentials sent with every request for a feed.
+// Secret is the basic auth password, the bearer token or the cookie
+// header value, depending on Type.
+type FeedAuth struct {
+	Type     AuthType
+	Username string
+	Secret   string
+}
+
+func (a FeedAuth) IsZero() bool {
+	return a.Type == AuthNone
+}
+
+// String describes the credentials with the secret masked, so that they
+// can be printed or logged safely.
+func (a FeedAuth) String() string {
+	switch a.Type {
+	case AuthNone:
+		return "none"
+	case AuthBasic:
+		return fmt.Sprintf("basic (%s:%s)", a.Username, MaskSecret(a.Secret))
+	default:
+		return fmt.Sprintf("%s (%s)", a.Type, MaskSecret(a.Secret))
+	}
+}
+
+// MaskSecret hides a secret entirely; only whether one is set shows.
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "********"
+}
+
+// sensitiveHeaderWords mark custom headers whose values are credentials.
+var sensitiveHeaderWords = []string{"auth", "cookie", "token", "key", "secret", "password", "session"}
+
+// IsSensitiveHeader reports whether the header name suggests its value is
+// a credential.
+func IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskHeader returns the value of a custom request header fit for display,
+// masking it when it carries a credential.
+func MaskHeader(name, value string) string {
+	if IsSensitiveHeader(name) {
+		return MaskSecret(value)
+	}
+	return value
+}
+
+// HasCredentials reports whether requests with auth and headers would
+// carry a secret.
+func HasCredentials(auth FeedAuth, headers map[string]string) bool {
+	if !auth.IsZero() {
+		return true
+	}
+	for name := range headers {
+		if IsSensitiveHeader(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Columns that hold encrypted secrets. The feed's proxy URL is encrypted
+// since it may carry a password; ProxyDirect is stored as is.
+const (
+	SecretColumnAuth    = "auth_secret"
+	SecretColumnHeaders = "headers"
+	SecretColumnProxy   = "proxy_url"
+)
+
+// SecretScope names where a secret is stored. It is bound into the
+// encryption, so a secret copied to another feed or column cannot be
+// decrypted there.
+func SecretScope(feedID uuid.UUID, column string) string {
+	return feedID.String() + "/" + column
+}
+
+// HeaderSecretScope is the scope of the value of one custom header.
+func HeaderSecretScope(feedID uuid.UUID, name string) string {
+	return SecretScope(feedID, SecretColumnHeaders+"/"+name)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// DefaultRedirectThreshold is how many fetches in a row must be permanently
+// redirected to the same place before a feed's URL is updated.
+const DefaultRedirectThreshold = 3
+
+// DefaultGoneThreshold is how many fetches in a row must fail with 404 or an
+// unknown host before a feed is considered gone.
+const DefaultGoneThreshold = 5
+
+// Default backoff bounds for feeds that keep failing.
+const (
+	DefaultBackoffBase = 5 * time.Minute
+	DefaultBackoffMax  = 24 * time.Hour
+)
+
+// FeedPolicy holds the rules the aggregator applies to a feed based on the
+// outcome of its fetches.
+type FeedPolicy struct {
+	// RedirectThreshold is the number of consecutive permanent redirects
+	// to one location after which the stored URL follows them. Zero
+	// disables URL updates.
+	RedirectThreshold int
+	// GoneThreshold is the number of consecutive 404 or unknown host
+	// failures after which a feed is marked gone. Zero leaves such feeds
+	// erroring forever.
+	GoneThreshold int
+	// BackoffBase is the delay before retrying a feed after its first
+	// failure; it doubles with every further failure up to BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// Backoff returns how long to wait before fetching a feed again after the
+// given number of consecutive failures.
+func (p FeedPolicy) Backoff(failures int) time.Duration {
+	if failures <= 0 || p.BackoffBase <= 0 {
+		return 0
+	}
+
+	limit := p.BackoffMax
+	if limit <= 0 {
+		limit = DefaultBackoffMax
+	}
+
+	delay := p.BackoffBase
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
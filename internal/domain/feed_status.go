@@ -0,0 +1,46 @@
+package domain
+
+import "fmt"
+
+// FeedStatus controls whether the aggregator schedules a feed.
+type FeedStatus string
+
+const (
+	// FeedStatusActive feeds are fetched normally.
+	FeedStatusActive FeedStatus = "active"
+	// FeedStatusPaused feeds were stopped by an operator.
+	FeedStatusPaused FeedStatus = "paused"
+	// FeedStatusGone feeds were removed by their publisher and are no
+	// longer fetched until an operator re-activates them.
+	FeedStatusGone FeedStatus = "gone"
+	// FeedStatusErroring feeds failed their last fetch but are still
+	// retried.
+	FeedStatusErroring FeedStatus = "erroring"
+)
+
+var feedStatuses = []FeedStatus{FeedStatusActive, FeedStatusPaused, FeedStatusGone, FeedStatusErroring}
+
+func ParseFeedStatus(value string) (FeedStatus, error) {
+	for _, status := range feedStatuses {
+		if string(status) == value {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown feed status: %s (want one of active, paused, gone, erroring)", value)
+}
+
+// IsScheduled reports whether feeds in this status are fetched.
+func (s FeedStatus) IsScheduled() bool {
+	return s == FeedStatusActive || s == FeedStatusErroring
+}
+
+// ScheduledFeedStatuses lists the statuses for which IsScheduled is true.
+func ScheduledFeedStatuses() []FeedStatus {
+	var scheduled []FeedStatus
+	for _, status := range feedStatuses {
+		if status.IsScheduled() {
+			scheduled = append(scheduled, status)
+		}
+	}
+	return scheduled
+}
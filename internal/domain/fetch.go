@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FetchRequest struct {
+	// FeedID scopes the decryption of the feed's stored secrets.
+	FeedID       uuid.UUID
+	URL          string
+	ETag         string
+	LastModified string
+	// Since lets the parser stop once it reaches items older than this,
+	// usually the newest article already stored for the feed.
+	Since   *time.Time
+	Auth    FeedAuth
+	Headers map[string]string
+	// ProxyURL overrides the configured outbound proxy; ProxyDirect
+	// bypasses it.
+	ProxyURL string
+}
+
+// FetchResult describes one fetch. When NotModified is set the server
+// answered 304 and Feed is nil.
+type FetchResult struct {
+	Feed         *ParsedFeed
+	StatusCode   int
+	NotModified  bool
+	ETag         string
+	LastModified string
+	BytesRead    int64
+	// Redirects lists the redirects followed to reach the final response,
+	// in order.
+	Redirects []Redirect
+}
+
+// Redirect is one hop of a redirect chain: a response with StatusCode
+// pointing to URL.
+type Redirect struct {
+	URL        string
+	StatusCode int
+}
+
+// PermanentRedirect returns the final URL when every hop of the redirect
+// chain was permanent (301 or 308), and an empty string otherwise.
+func (r *FetchResult) PermanentRedirect() string {
+	if len(r.Redirects) == 0 {
+		return ""
+	}
+	for _, redirect := range r.Redirects {
+		if redirect.StatusCode != 301 && redirect.StatusCode != 308 {
+			return ""
+		}
+	}
+	return r.Redirects[len(r.Redirects)-1].URL
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultFetchHistoryRetention is how long fetch runs are kept.
+const DefaultFetchHistoryRetention = 30 * 24 * time.Hour
+
+// FetchRun records one attempt of the aggregator to fetch a feed.
+type FetchRun struct {
+	ID          uuid.UUID
+	FeedID      uuid.UUID
+	FeedName    string
+	StartedAt   time.Time
+	Duration    time.Duration
+	StatusCode  int
+	BytesRead   int64
+	ItemsSeen   int
+	NewArticles int
+	// Truncated is set when the size or item limit cut the feed short.
+	Truncated bool
+	Error     string
+}
+
+func NewFetchRun(feed *Feed) *FetchRun {
+	return &FetchRun{
+		ID:        uuid.New(),
+		FeedID:    feed.ID,
+		FeedName:  feed.Name,
+		StartedAt: time.Now(),
+	}
+}
+
+// Finish stamps the run's duration and the error that ended it, if any.
+func (r *FetchRun) Finish(err error) {
+	r.Duration = time.Since(r.StartedAt)
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+func (r *FetchRun) Failed() bool {
+	return r.Error != ""
+}
+
+// FetchRunFilter narrows a fetch history query. An empty FeedName matches
+// every feed.
+type FetchRunFilter struct {
+	FeedName   string
+	FailedOnly bool
+	Limit      int
+}
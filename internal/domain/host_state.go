@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HostState is what the aggregator knows about one remote host: how hard
+// it is being fetched, whether it asked us to back off and whether its
+// circuit breaker has tripped.
+type HostState struct {
+	Host             string
+	InFlight         int
+	Requests         int64
+	PausedUntil      *time.Time
+	PauseReason      string
+	Breaker          BreakerState
+	BreakerFailures  int
+	BreakerOpenUntil *time.Time
+	UpdatedAt        time.Time
+}
+
+// IsPaused reports whether fetches to the host are held back at now.
+func (h *HostState) IsPaused(now time.Time) bool {
+	return h.PausedUntil != nil && now.Before(*h.PausedUntil)
+}
+
+// IsBreakerOpen reports whether fetches to the host are skipped at now
+// because its circuit breaker is open.
+func (h *HostState) IsBreakerOpen(now time.Time) bool {
+	return h.BreakerOpenUntil != nil && now.Before(*h.BreakerOpenUntil)
+}
+
+// HostThrottledError is returned instead of fetching while a host is paused
+// after it answered 429 or 503.
+type HostThrottledError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *HostThrottledError) Error() string {
+	return fmt.Sprintf("host %s is throttled until %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+// HostOf returns the lower-cased host name of rawURL, which is how
+// per-host limits are keyed.
+func HostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return rawURL
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// BreakerState is the state of a host's circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitOpenError is returned instead of fetching while a host's circuit
+// breaker is open after repeated failures.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit for host %s is open until %s", e.Host, e.Until.Format(time.RFC3339))
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type JSONFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Description string           `json:"description"`
+	Items       []JSONFeedItem   `json:"items"`
+	Authors     []JSONFeedAuthor `json:"authors"`
+}
+
+type JSONFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Tags          []string             `json:"tags"`
+	Authors       []JSONFeedAuthor     `json:"authors"`
+	Author        *JSONFeedAuthor      `json:"author"`
+	Attachments   []JSONFeedAttachment `json:"attachments"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type JSONFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	Title             string  `json:"title"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+func (f *JSONFeed) ToParsedFeed() *ParsedFeed {
+	parsed := &ParsedFeed{
+		Title:       f.Title,
+		Link:        f.HomePageURL,
+		Description: f.Description,
+		Items:       make([]FeedItem, 0, len(f.Items)),
+	}
+
+	for _, item := range f.Items {
+		parsed.Items = append(parsed.Items, item.toFeedItem(f.Authors))
+	}
+
+	return parsed
+}
+
+func (item *JSONFeedItem) toFeedItem(feedAuthors []JSONFeedAuthor) FeedItem {
+	link := item.URL
+	if link == "" {
+		link = item.ExternalURL
+	}
+
+	description := firstNonEmpty(item.ContentHTML, item.ContentText, item.Summary)
+
+	// JSON Feed 1.0 used a single "author" object; 1.1 replaced it with
+	// "authors" and lets items inherit the feed-level list.
+	authors := item.Authors
+	if len(authors) == 0 && item.Author != nil {
+		authors = []JSONFeedAuthor{*item.Author}
+	}
+	if len(authors) == 0 {
+		authors = feedAuthors
+	}
+
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		if author.Name != "" {
+			names = append(names, author.Name)
+		}
+	}
+
+	enclosures := make([]Enclosure, 0, len(item.Attachments))
+	for _, attachment := range item.Attachments {
+		enclosures = append(enclosures, Enclosure{
+			URL:      attachment.URL,
+			MimeType: attachment.MimeType,
+			Length:   attachment.SizeInBytes,
+			Duration: time.Duration(attachment.DurationInSeconds * float64(time.Second)),
+		})
+	}
+
+	return FeedItem{
+		GUID:        item.ID,
+		Title:       item.Title,
+		Link:        link,
+		Description: description,
+		PubDate:     item.PubDate(),
+		Author:      strings.Join(names, ", "),
+		Categories:  normalizeCategories(item.Tags),
+		Content:     firstNonEmpty(item.ContentHTML, item.ContentText),
+		Enclosures:  enclosures,
+	}
+}
+
+func (item *JSONFeedItem) PubDate() string {
+	return firstNonEmpty(item.DatePublished, item.DateModified)
+}
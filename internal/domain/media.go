@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// MediaContent is a Media RSS (http://search.yahoo.com/mrss/) content
+// element. Numeric attributes are kept as strings because publishers fill
+// them with empty or malformed values often enough that strict decoding
+// would reject the whole document.
+type MediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	Medium     string           `xml:"medium,attr"`
+	FileSize   string           `xml:"fileSize,attr"`
+	Duration   string           `xml:"duration,attr"`
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type MediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type MediaGroup struct {
+	Contents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// MediaElements collects the enclosure-like elements an item can carry in
+// RSS 2.0 and Atom documents.
+type MediaElements struct {
+	Enclosures      []RSSEnclosure   `xml:"enclosure"`
+	MediaContents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+	ITunesDuration  string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesImage     ITunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+func (m *MediaElements) toEnclosures() []Enclosure {
+	var enclosures []Enclosure
+	seen := make(map[string]bool)
+	add := func(enclosure Enclosure) {
+		if enclosure.URL == "" || seen[enclosure.URL] {
+			return
+		}
+		seen[enclosure.URL] = true
+		enclosures = append(enclosures, enclosure)
+	}
+
+	for _, enclosure := range m.Enclosures {
+		add(Enclosure{
+			URL:      strings.TrimSpace(enclosure.URL),
+			MimeType: enclosure.Type,
+			Length:   parseInt64(enclosure.Length),
+		})
+	}
+
+	thumbnail := firstThumbnail(m.MediaThumbnails)
+	contents := m.MediaContents
+	for _, group := range m.MediaGroups {
+		contents = append(contents, group.Contents...)
+		if thumbnail == "" {
+			thumbnail = firstThumbnail(group.Thumbnails)
+		}
+	}
+	if thumbnail == "" {
+		thumbnail = strings.TrimSpace(m.ITunesImage.Href)
+	}
+
+	for _, content := range contents {
+		mimeType := content.Type
+		if mimeType == "" {
+			mimeType = content.Medium
+		}
+		add(Enclosure{
+			URL:          strings.TrimSpace(content.URL),
+			MimeType:     mimeType,
+			Length:       parseInt64(content.FileSize),
+			Duration:     parseMediaDuration(content.Duration),
+			ThumbnailURL: firstThumbnail(content.Thumbnails),
+		})
+	}
+
+	duration := parseMediaDuration(m.ITunesDuration)
+	for i := range enclosures {
+		if enclosures[i].Duration == 0 {
+			enclosures[i].Duration = duration
+		}
+		if enclosures[i].ThumbnailURL == "" {
+			enclosures[i].ThumbnailURL = thumbnail
+		}
+	}
+
+	return enclosures
+}
+
+func firstThumbnail(thumbnails []MediaThumbnail) string {
+	for _, thumbnail := range thumbnails {
+		if url := strings.TrimSpace(thumbnail.URL); url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+func parseInt64(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseMediaDuration accepts plain seconds ("3723", "3723.5") as well as
+// the clock forms itunes:duration allows ("62:03", "1:02:03").
+func parseMediaDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var seconds float64
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ParsedFeed is the format-independent view of a fetched feed. Every
+// supported syndication format is mapped into it before the aggregator
+// sees the items.
+type ParsedFeed struct {
+	Title       string
+	Link        string
+	Description string
+	Items       []FeedItem
+	// Warnings lists problems that were recovered from while parsing.
+	Warnings []string
+	// Truncated is set when a size or item limit cut the feed short.
+	Truncated bool
+	// Schedule carries the publisher's polling hints.
+	Schedule ScheduleHints
+}
+
+type FeedItem struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	PubDate     string
+	Author      string
+	Categories  []string
+	Content     string
+	Enclosures  []Enclosure
+}
+
+type Enclosure struct {
+	URL          string
+	MimeType     string
+	Length       int64
+	Duration     time.Duration
+	ThumbnailURL string
+}
+
+func (item *FeedItem) ParsePubDate() *time.Time {
+	return ParseDate(item.PubDate)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeCategories trims category labels and drops empty and repeated
+// ones, keeping the order the publisher used.
+func normalizeCategories(categories []string) []string {
+	seen := make(map[string]bool, len(categories))
+	normalized := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		normalized = append(normalized, category)
+	}
+	return normalized
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ProxyDirect as a feed's proxy makes its requests bypass the configured
+// outbound proxy.
+const ProxyDirect = "direct"
+
+// ValidateProxyURL checks that raw is an HTTP, HTTPS or SOCKS5 proxy URL.
+func ValidateProxyURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", parsed.Redacted())
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q: missing host", parsed.Redacted())
+	}
+	return nil
+}
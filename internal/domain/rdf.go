@@ -0,0 +1,54 @@
+package domain
+
+import "encoding/xml"
+
+// RDFFeed is an RSS 1.0 document. Unlike RSS 2.0, items are siblings of
+// the channel element rather than its children.
+type RDFFeed struct {
+	XMLName xml.Name   `xml:"RDF"`
+	Channel RDFChannel `xml:"channel"`
+	Items   []RDFItem  `xml:"item"`
+}
+
+type RDFChannel struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	SyndicationElements
+}
+
+type RDFItem struct {
+	About          string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title          string   `xml:"title"`
+	Link           string   `xml:"link"`
+	Description    string   `xml:"description"`
+	Date           string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator        string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects       []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	ContentEncoded string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+func (f *RDFFeed) ToParsedFeed() *ParsedFeed {
+	parsed := &ParsedFeed{
+		Title:       f.Channel.Title,
+		Link:        f.Channel.Link,
+		Description: f.Channel.Description,
+		Items:       make([]FeedItem, 0, len(f.Items)),
+		Schedule:    ScheduleHints{UpdatePeriod: f.Channel.updateInterval()},
+	}
+
+	for _, item := range f.Items {
+		parsed.Items = append(parsed.Items, FeedItem{
+			GUID:        item.About,
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			PubDate:     item.Date,
+			Author:      item.Creator,
+			Categories:  normalizeCategories(item.Subjects),
+			Content:     item.ContentEncoded,
+		})
+	}
+
+	return parsed
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/xml"
+	"strings"
 	"time"
 )
 
@@ -11,35 +12,68 @@ type RSSFeed struct {
 }
 
 type RSSChannel struct {
-	Title       string    `xml:"title"`
-	Link        string    `xml:"link"`
-	Description string    `xml:"description"`
-	Items       []RSSItem `xml:"item"`
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	TTL         string       `xml:"ttl"`
+	SkipHours   RSSSkipHours `xml:"skipHours"`
+	SkipDays    RSSSkipDays  `xml:"skipDays"`
+	Items       []RSSItem    `xml:"item"`
+	SyndicationElements
+}
+
+type RSSSkipHours struct {
+	Hours []string `xml:"hour"`
+}
+
+type RSSSkipDays struct {
+	Days []string `xml:"day"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title          string   `xml:"title"`
+	Link           string   `xml:"link"`
+	Description    string   `xml:"description"`
+	PubDate        string   `xml:"pubDate"`
+	GUID           string   `xml:"guid"`
+	Author         string   `xml:"author"`
+	Creator        string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories     []string `xml:"category"`
+	ContentEncoded string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	MediaElements
 }
 
 func (item *RSSItem) ParsePubDate() *time.Time {
-	formats := []string{
-		time.RFC1123,
-		time.RFC1123Z,
-		time.RFC822,
-		time.RFC822Z,
-		time.RFC3339,
-		"Mon, 2 Jan 2006 15:04:05 -0700",
-		"Mon, 2 Jan 2006 15:04:05 MST",
+	return ParseDate(item.PubDate)
+}
+
+func (f *RSSFeed) ToParsedFeed() *ParsedFeed {
+	parsed := &ParsedFeed{
+		Title:       f.Channel.Title,
+		Link:        f.Channel.Link,
+		Description: f.Channel.Description,
+		Items:       make([]FeedItem, 0, len(f.Channel.Items)),
+		Schedule: ScheduleHints{
+			TTL:          parseTTL(f.Channel.TTL),
+			UpdatePeriod: f.Channel.updateInterval(),
+			SkipHours:    parseSkipHours(f.Channel.SkipHours.Hours),
+			SkipDays:     parseSkipDays(f.Channel.SkipDays.Days),
+		},
 	}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, item.PubDate); err == nil {
-			return &t
-		}
+	for _, item := range f.Channel.Items {
+		parsed.Items = append(parsed.Items, FeedItem{
+			GUID:        strings.TrimSpace(item.GUID),
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			PubDate:     item.PubDate,
+			Author:      firstNonEmpty(item.Creator, item.Author),
+			Categories:  normalizeCategories(item.Categories),
+			Content:     item.ContentEncoded,
+			Enclosures:  item.toEnclosures(),
+		})
 	}
 
-	return nil
+	return parsed
 }
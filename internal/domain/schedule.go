@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxHintInterval caps how far apart publisher hints may space fetches, so
+// that a "yearly" update period cannot freeze a feed.
+const MaxHintInterval = 24 * time.Hour
+
+// ScheduleHints are the publisher's suggestions on how often to poll a
+// feed: RSS ttl, skipHours and skipDays, and the syndication module's
+// update period. Skip hours and days are in UTC.
+type ScheduleHints struct {
+	TTL          time.Duration
+	UpdatePeriod time.Duration
+	SkipHours    []int
+	SkipDays     []time.Weekday
+}
+
+func (h ScheduleHints) IsZero() bool {
+	return h.TTL == 0 && h.UpdatePeriod == 0 && len(h.SkipHours) == 0 && len(h.SkipDays) == 0
+}
+
+// MinInterval is the shortest polling interval the publisher asked for.
+func (h ScheduleHints) MinInterval() time.Duration {
+	return min(max(h.TTL, h.UpdatePeriod), MaxHintInterval)
+}
+
+// Skips reports whether the publisher asked not to be polled at t.
+func (h ScheduleHints) Skips(t time.Time) bool {
+	t = t.UTC()
+	for _, hour := range h.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range h.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// NextFetch returns the earliest time after from, at least interval later,
+// that the publisher does not ask to skip. When every hour of the week is
+// skipped the hints are ignored.
+func (h ScheduleHints) NextFetch(from time.Time, interval time.Duration) time.Time {
+	next := from.Add(max(interval, h.MinInterval()))
+	for i := 0; i < 7*24 && h.Skips(next); i++ {
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	if h.Skips(next) {
+		return from.Add(interval)
+	}
+	return next
+}
+
+// SyndicationElements holds the syndication module (sy:) elements that
+// RSS 1.0, RSS 2.0 and Atom feeds use to announce their update rhythm.
+type SyndicationElements struct {
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+// SyndicationNS is the namespace of the syndication module.
+const SyndicationNS = "http://purl.org/rss/1.0/modules/syndication/"
+
+// updateInterval turns sy:updatePeriod and sy:updateFrequency into the
+// time between updates. The frequency defaults to once per period.
+func (s SyndicationElements) updateInterval() time.Duration {
+	var period time.Duration
+	switch strings.ToLower(strings.TrimSpace(s.UpdatePeriod)) {
+	case "hourly":
+		period = time.Hour
+	case "daily":
+		period = 24 * time.Hour
+	case "weekly":
+		period = 7 * 24 * time.Hour
+	case "monthly":
+		period = 30 * 24 * time.Hour
+	case "yearly":
+		period = 365 * 24 * time.Hour
+	default:
+		return 0
+	}
+
+	frequency, err := strconv.Atoi(strings.TrimSpace(s.UpdateFrequency))
+	if err != nil || frequency <= 0 {
+		frequency = 1
+	}
+	return period / time.Duration(frequency)
+}
+
+// parseTTL reads an RSS ttl, given in minutes.
+func parseTTL(value string) time.Duration {
+	minutes, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// parseSkipHours keeps the valid hours of an RSS skipHours element. Both
+// 0-23 and the 1-24 numbering some publishers use are accepted.
+func parseSkipHours(values []string) []int {
+	var hours []int
+	for _, value := range values {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || hour < 0 || hour > 24 {
+			continue
+		}
+		hours = append(hours, hour%24)
+	}
+	return hours
+}
+
+func parseSkipDays(values []string) []time.Weekday {
+	var days []time.Weekday
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(value, day.String()) {
+				days = append(days, day)
+				break
+			}
+		}
+	}
+	return days
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// StoryMaxDistance is the largest Hamming distance between two
+	// fingerprints that still counts as the same story. Unrelated texts
+	// land around 32 bits apart.
+	StoryMaxDistance = 12
+	// StoryWindow bounds how far back a new article looks for a story to
+	// join; coverage of one event rarely spans more than a few days.
+	StoryWindow = 72 * time.Hour
+
+	titleWeight            = 4
+	maxDescriptionFeatures = 40
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "are": true, "was": true, "were": true,
+	"has": true, "have": true, "its": true, "into": true, "after": true,
+	"over": true, "about": true, "will": true, "new": true, "but": true,
+	"not": true, "you": true, "your": true, "our": true, "their": true,
+}
+
+type StoryFingerprint struct {
+	ArticleID   uuid.UUID
+	FeedID      uuid.UUID
+	StoryID     uuid.UUID
+	Fingerprint uint64
+}
+
+// SimHash computes a 64-bit locality-sensitive fingerprint of an article.
+// Outlets rewrite the body of a story far more than its headline, so title
+// words and word pairs outweigh the opening of the description. Zero means
+// there was no usable text.
+func SimHash(title, description string) uint64 {
+	var weights [64]int
+	add := func(feature string, weight int) {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		hash := hasher.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if hash&(1<<uint(bit)) != 0 {
+				weights[bit] += weight
+			} else {
+				weights[bit] -= weight
+			}
+		}
+	}
+
+	titleTokens := tokenize(title)
+	for i, token := range titleTokens {
+		add(token, titleWeight)
+		if i > 0 {
+			add(titleTokens[i-1]+" "+token, titleWeight)
+		}
+	}
+
+	descriptionTokens := tokenize(htmlTag.ReplaceAllString(description, " "))
+	if len(descriptionTokens) > maxDescriptionFeatures {
+		descriptionTokens = descriptionTokens[:maxDescriptionFeatures]
+	}
+	for _, token := range descriptionTokens {
+		add(token, 1)
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
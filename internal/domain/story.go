@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Story is a group of near-duplicate articles published by different
+// feeds. The first source is the earliest article and supplies the title.
+type Story struct {
+	ID      uuid.UUID
+	Sources []StorySource
+}
+
+type StorySource struct {
+	ArticleID   uuid.UUID
+	FeedName    string
+	Title       string
+	Link        string
+	PublishedAt *time.Time
+	CreatedAt   time.Time
+}
+
+func (s *Story) Headline() StorySource {
+	return s.Sources[0]
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"rsshub/internal/domain"
 
@@ -12,5 +13,11 @@ type ArticleRepository interface {
 	Create(ctx context.Context, article *domain.Article) error
 	CreateBatch(ctx context.Context, articles []*domain.Article) error
 	GetByFeedName(ctx context.Context, feedName string, limit int) ([]*domain.Article, error)
-	Exists(ctx context.Context, link string, feedID uuid.UUID) (bool, error)
+	GetByCategory(ctx context.Context, feedName, category string, limit int) ([]*domain.Article, error)
+	Exists(ctx context.Context, dedupKey, linkKey string, feedID uuid.UUID) (bool, error)
+	GetRecentFingerprints(ctx context.Context, since time.Time) ([]domain.StoryFingerprint, error)
+	GetStories(ctx context.Context, limit int) ([]*domain.Story, error)
+	GetLatestPublishedAt(ctx context.Context, feedID uuid.UUID) (*time.Time, error)
+	ListNonCanonicalKeys(ctx context.Context, feedID uuid.UUID, limit int) ([]*domain.Article, error)
+	UpdateDedupKeys(ctx context.Context, dedupKeys map[uuid.UUID]string) error
 }
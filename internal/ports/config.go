@@ -11,4 +11,23 @@ type ConfigProvider interface {
 	GetPostgresSSLMode() string
 	GetDefaultInterval() time.Duration
 	GetDefaultWorkersCount() int
+	GetStrippedParams() []string
+	GetMaxFeedBytes() int64
+	GetMaxFeedItems() int
+	GetRedirectThreshold() int
+	GetGoneThreshold() int
+	GetBackoffBase() time.Duration
+	GetBackoffMax() time.Duration
+	GetFetchHistoryRetention() time.Duration
+	GetHostMaxConcurrent() int
+	GetHostMinDelay() time.Duration
+	GetBreakerThreshold() int
+	GetBreakerCooldown() time.Duration
+	GetProxyURL() string
+	GetCABundlePath() string
+	GetClientCertPath() string
+	GetClientKeyPath() string
+	GetMinTLSVersion() string
+	GetSecretKey() (string, error)
+	GetPreviousSecretKey() (string, error)
 }
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"rsshub/internal/domain"
+)
+
+type FeedDiscoverer interface {
+	Discover(ctx context.Context, req *domain.FetchRequest) ([]string, error)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"rsshub/internal/domain"
 
@@ -16,5 +17,9 @@ type FeedRepository interface {
 	ListAll(ctx context.Context) ([]*domain.Feed, error)
 	Delete(ctx context.Context, name string) error
 	Update(ctx context.Context, feed *domain.Feed) error
+	UpdateStatus(ctx context.Context, name string, status domain.FeedStatus) error
+	UpdateIntervalOverride(ctx context.Context, name string, interval time.Duration) error
+	UpdateRequestOptions(ctx context.Context, feed *domain.Feed) error
+	ListByStatus(ctx context.Context, status domain.FeedStatus) ([]*domain.Feed, error)
 	GetMostOutdated(ctx context.Context, limit int) ([]*domain.Feed, error)
 }
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"rsshub/internal/domain"
+)
+
+type FetchRunRepository interface {
+	Create(ctx context.Context, run *domain.FetchRun) error
+	List(ctx context.Context, filter domain.FetchRunFilter) ([]*domain.FetchRun, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
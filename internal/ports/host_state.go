@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"rsshub/internal/domain"
+)
+
+// HostStateReporter is implemented by fetchers that keep per-host state,
+// such as rate limiters, so the aggregator can publish it to operators.
+type HostStateReporter interface {
+	HostStates() []domain.HostState
+}
+
+type HostStateRepository interface {
+	// Replace stores states as the complete current set of host states.
+	Replace(ctx context.Context, states []domain.HostState) error
+	List(ctx context.Context) ([]domain.HostState, error)
+}
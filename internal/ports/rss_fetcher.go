@@ -7,5 +7,5 @@ import (
 )
 
 type RSSFetcher interface {
-	Fetch(ctx context.Context, url string) (*domain.RSSFeed, error)
+	Fetch(ctx context.Context, req *domain.FetchRequest) (*domain.FetchResult, error)
 }
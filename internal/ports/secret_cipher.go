@@ -0,0 +1,12 @@
+package ports
+
+// SecretCipher encrypts feed credentials for storage. Only the fetcher
+// decrypts them, right before a request is sent. The scope names where a
+// secret is stored, see domain.SecretScope, and must match on decryption.
+type SecretCipher interface {
+	Encrypt(plaintext, scope string) (string, error)
+	Decrypt(ciphertext, scope string) (string, error)
+	// Reencrypt encrypts a stored secret again under the current key
+	// without handing the plaintext back to the caller.
+	Reencrypt(ciphertext, scope string) (string, error)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS etag TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS last_modified TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_categories;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS categories,
+DROP COLUMN IF EXISTS content,
+DROP COLUMN IF EXISTS author,
+DROP COLUMN IF EXISTS guid;
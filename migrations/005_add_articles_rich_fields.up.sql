@@ -0,0 +1,7 @@
+ALTER TABLE articles
+ADD COLUMN IF NOT EXISTS guid TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS author TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS content TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS categories TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_articles_categories ON articles USING GIN (categories);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_article_enclosures_article_id;
+
+DROP TABLE IF EXISTS article_enclosures;
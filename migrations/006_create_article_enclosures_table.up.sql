@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS article_enclosures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    mime_type TEXT NOT NULL DEFAULT '',
+    length BIGINT NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    thumbnail_url TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_article_enclosures_article_id ON article_enclosures (article_id);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_articles_feed_dedup_key;
+
+DELETE FROM articles a
+USING articles b
+WHERE
+    a.feed_id = b.feed_id
+    AND a.link = b.link
+    AND a.created_at > b.created_at;
+
+ALTER TABLE articles ADD CONSTRAINT articles_link_feed_id_key UNIQUE (link, feed_id);
+
+ALTER TABLE articles DROP COLUMN IF EXISTS dedup_key;
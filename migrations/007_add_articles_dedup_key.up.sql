@@ -0,0 +1,24 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS dedup_key TEXT;
+
+-- Existing rows are keyed by guid where it is unique within the feed and by
+-- link otherwise, since a repeated guid cannot back a unique index.
+UPDATE articles a
+SET
+    dedup_key = CASE
+        WHEN a.guid <> ''
+        AND NOT EXISTS (
+            SELECT 1
+            FROM articles b
+            WHERE
+                b.feed_id = a.feed_id
+                AND b.guid = a.guid
+                AND b.id <> a.id
+        ) THEN 'guid:' || a.guid
+        ELSE 'link:' || a.link
+    END;
+
+ALTER TABLE articles ALTER COLUMN dedup_key SET NOT NULL;
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_link_feed_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_feed_dedup_key ON articles (feed_id, dedup_key);
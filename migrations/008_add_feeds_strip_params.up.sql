@@ -0,0 +1,16 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS strip_params TEXT[] NOT NULL DEFAULT '{}';
+
+-- Link-based dedup keys no longer include the scheme.
+UPDATE articles
+SET
+    dedup_key = regexp_replace(dedup_key, '^link:[a-z]+:', 'link:')
+WHERE
+    dedup_key LIKE 'link:%://%'
+    AND NOT EXISTS (
+        SELECT 1
+        FROM articles other
+        WHERE
+            other.feed_id = articles.feed_id
+            AND other.dedup_key = regexp_replace(articles.dedup_key, '^link:[a-z]+:', 'link:')
+    );
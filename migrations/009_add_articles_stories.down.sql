@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_story_id;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS story_id,
+DROP COLUMN IF EXISTS fingerprint;
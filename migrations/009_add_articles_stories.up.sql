@@ -0,0 +1,9 @@
+ALTER TABLE articles
+ADD COLUMN IF NOT EXISTS fingerprint BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS story_id UUID;
+
+UPDATE articles SET story_id = id WHERE story_id IS NULL;
+
+ALTER TABLE articles ALTER COLUMN story_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_articles_story_id ON articles (story_id);
@@ -0,0 +1,4 @@
+UPDATE articles SET published_at = NULL WHERE published_at_inferred;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS published_at_inferred;
@@ -0,0 +1,6 @@
+ALTER TABLE articles
+ADD COLUMN IF NOT EXISTS published_at_inferred BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE articles
+SET published_at = created_at, published_at_inferred = TRUE
+WHERE published_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS feed_url_history;
+
+ALTER TABLE feeds
+DROP COLUMN IF EXISTS redirect_count,
+DROP COLUMN IF EXISTS redirect_url;
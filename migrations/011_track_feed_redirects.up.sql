@@ -0,0 +1,13 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS redirect_url TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS redirect_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS feed_url_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    old_url TEXT NOT NULL,
+    new_url TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_feed_url_history_feed_id ON feed_url_history (feed_id);
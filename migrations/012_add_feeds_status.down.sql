@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_feeds_status;
+
+ALTER TABLE feeds
+DROP COLUMN IF EXISTS not_found_count,
+DROP COLUMN IF EXISTS status;
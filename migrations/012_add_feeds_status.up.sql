@@ -0,0 +1,6 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'paused', 'gone', 'erroring')),
+ADD COLUMN IF NOT EXISTS not_found_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_feeds_status ON feeds (status);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_feeds_next_fetch_at;
+
+ALTER TABLE feeds
+DROP COLUMN IF EXISTS next_fetch_at,
+DROP COLUMN IF EXISTS last_error_at,
+DROP COLUMN IF EXISTS last_error,
+DROP COLUMN IF EXISTS consecutive_failures;
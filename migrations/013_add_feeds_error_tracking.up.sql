@@ -0,0 +1,7 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS last_error_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS next_fetch_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_feeds_next_fetch_at ON feeds (next_fetch_at);
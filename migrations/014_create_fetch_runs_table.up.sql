@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS fetch_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    http_status INTEGER NOT NULL DEFAULT 0,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    items_seen INTEGER NOT NULL DEFAULT 0,
+    new_articles INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_fetch_runs_started_at ON fetch_runs (started_at);
+
+CREATE INDEX idx_fetch_runs_feed_id_started_at ON fetch_runs (feed_id, started_at DESC);
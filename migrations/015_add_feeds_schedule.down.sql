@@ -0,0 +1,6 @@
+ALTER TABLE feeds
+DROP COLUMN IF EXISTS interval_override_seconds,
+DROP COLUMN IF EXISTS skip_days,
+DROP COLUMN IF EXISTS skip_hours,
+DROP COLUMN IF EXISTS update_period_seconds,
+DROP COLUMN IF EXISTS ttl_seconds;
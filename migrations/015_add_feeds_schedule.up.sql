@@ -0,0 +1,6 @@
+ALTER TABLE feeds
+ADD COLUMN IF NOT EXISTS ttl_seconds INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS update_period_seconds INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS skip_hours INTEGER[] NOT NULL DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS skip_days INTEGER[] NOT NULL DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS interval_override_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS host_states (
+    host TEXT PRIMARY KEY,
+    in_flight INTEGER NOT NULL DEFAULT 0,
+    requests BIGINT NOT NULL DEFAULT 0,
+    paused_until TIMESTAMP,
+    pause_reason TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
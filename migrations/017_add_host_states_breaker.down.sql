@@ -0,0 +1,4 @@
+ALTER TABLE host_states
+    DROP COLUMN IF EXISTS breaker_open_until,
+    DROP COLUMN IF EXISTS breaker_failures,
+    DROP COLUMN IF EXISTS breaker;
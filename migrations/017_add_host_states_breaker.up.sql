@@ -0,0 +1,4 @@
+ALTER TABLE host_states
+    ADD COLUMN IF NOT EXISTS breaker TEXT NOT NULL DEFAULT 'closed',
+    ADD COLUMN IF NOT EXISTS breaker_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS breaker_open_until TIMESTAMP;
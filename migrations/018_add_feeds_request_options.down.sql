@@ -0,0 +1,5 @@
+ALTER TABLE feeds
+    DROP COLUMN IF EXISTS headers,
+    DROP COLUMN IF EXISTS auth_secret,
+    DROP COLUMN IF EXISTS auth_username,
+    DROP COLUMN IF EXISTS auth_type;
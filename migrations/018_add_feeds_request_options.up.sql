@@ -0,0 +1,6 @@
+ALTER TABLE feeds
+    ADD COLUMN IF NOT EXISTS auth_type TEXT NOT NULL DEFAULT ''
+        CHECK (auth_type IN ('', 'basic', 'bearer', 'cookie')),
+    ADD COLUMN IF NOT EXISTS auth_username TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS auth_secret TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1 @@
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS proxy_url TEXT NOT NULL DEFAULT '';
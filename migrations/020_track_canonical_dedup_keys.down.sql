@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_articles_dedup_key_not_canonical;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS dedup_key_canonical;
@@ -0,0 +1,12 @@
+-- Link keys written before canonicalization are rewritten by the aggregator
+-- with the configured canonicalizer, which SQL cannot reproduce. Rows added
+-- from now on are canonical already.
+ALTER TABLE articles
+ADD COLUMN IF NOT EXISTS dedup_key_canonical BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE articles ALTER COLUMN dedup_key_canonical SET DEFAULT TRUE;
+
+UPDATE articles SET dedup_key_canonical = TRUE WHERE dedup_key NOT LIKE 'link:%';
+
+CREATE INDEX IF NOT EXISTS idx_articles_dedup_key_not_canonical
+ON articles (feed_id) WHERE NOT dedup_key_canonical;
@@ -0,0 +1,4 @@
+-- Covers the recent-fingerprint lookup run for every batch of new articles.
+CREATE INDEX IF NOT EXISTS idx_articles_fingerprint_time
+ON articles ((COALESCE(published_at, created_at)))
+WHERE fingerprint <> 0;
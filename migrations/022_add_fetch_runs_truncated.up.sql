@@ -0,0 +1,2 @@
+ALTER TABLE fetch_runs
+    ADD COLUMN IF NOT EXISTS truncated BOOLEAN NOT NULL DEFAULT FALSE;